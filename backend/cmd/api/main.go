@@ -2,12 +2,18 @@ package main
 
 import (
 	"context"
+	"time"
 
 	"github.com/neevan0842/BlogSphere/backend/config"
 	"github.com/neevan0842/BlogSphere/backend/database"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 	"github.com/neevan0842/BlogSphere/backend/internal"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
+	"github.com/neevan0842/BlogSphere/backend/internal/scheduler"
 	"github.com/neevan0842/BlogSphere/backend/logger"
 	"github.com/neevan0842/BlogSphere/backend/mailer"
+	"github.com/neevan0842/BlogSphere/backend/utils"
 )
 
 func main() {
@@ -31,11 +37,39 @@ func main() {
 	}
 	log.Info("connected to database pool successfully")
 
+	// JWT signing keys
+	if err := utils.LoadSigningKeys(config.Envs.JWT_KEYS_DIR, config.Envs.JWT_SIGNING_KEY_ID); err != nil {
+		log.Fatal("Unable to load JWT signing keys: ", err)
+	}
+
+	// Scheduled post publisher
+	publishInterval, err := time.ParseDuration(config.Envs.PUBLISH_SCHEDULER_INTERVAL)
+	if err != nil {
+		log.Fatal("Invalid publish scheduler interval: ", err)
+	}
+	publisher := scheduler.NewPostPublisher(sqlc.New(pool), log, publishInterval)
+	go publisher.Start(ctx)
+
+	// Purger for deleted content past its restore window
+	purgeInterval, err := time.ParseDuration(config.Envs.PURGE_SCHEDULER_INTERVAL)
+	if err != nil {
+		log.Fatal("Invalid purge scheduler interval: ", err)
+	}
+	purger := scheduler.NewDeletedPurger(sqlc.New(pool), log, purgeInterval, common.RestoreWindow())
+	go purger.Start(ctx)
+
 	// Mailer
 	mail := mailer.NewMailer(log)
 
+	// Content filter for new and edited posts and comments
+	duplicateWindow, err := time.ParseDuration(config.Envs.CONTENT_FILTER_DUPLICATE_WINDOW)
+	if err != nil {
+		log.Fatal("Invalid content filter duplicate window: ", err)
+	}
+	filter := contentfilter.NewDefaultPipeline(sqlc.New(pool), duplicateWindow)
+
 	// API Server
-	server := internal.NewAPIServer(config.Envs.ADDR, pool, log, mail)
+	server := internal.NewAPIServer(config.Envs.ADDR, pool, log, mail, filter)
 
 	// Run the server
 	log.Fatal(server.Run(server.Mount()))
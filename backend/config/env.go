@@ -29,10 +29,20 @@ type Config struct {
 	GOOGLE_CLIENT_SECRET string
 	GOOGLE_REDIRECT_URI  string
 
+	// GitHub OAuth configuration
+	GITHUB_CLIENT_ID     string
+	GITHUB_CLIENT_SECRET string
+	GITHUB_REDIRECT_URI  string
+
 	// JWT Configuration
 	JWT_SECRET                   string
 	ACCESS_TOKEN_EXPIRE_MINUTES  int64
 	REFRESH_TOKEN_EXPIRE_MINUTES int64
+	JWT_KEYS_DIR                 string
+	JWT_SIGNING_KEY_ID           string
+
+	// Admin Configuration
+	ADMIN_EMAILS string
 
 	// Cookie Configuration
 	Secure bool
@@ -43,6 +53,31 @@ type Config struct {
 	// MailerSend Configuration
 	MAILERSEND_API_KEY string
 	FROM_EMAIL         string
+
+	// Scheduler Configuration
+	PUBLISH_SCHEDULER_INTERVAL string
+	PURGE_SCHEDULER_INTERVAL   string
+
+	// Soft delete Configuration
+	RESTORE_WINDOW_DAYS int64
+
+	// Content filter Configuration
+	CONTENT_FILTER_ENABLED              bool
+	CONTENT_FILTER_BLOCKED_WORDS        string
+	CONTENT_FILTER_FLAGGED_WORDS        string
+	CONTENT_FILTER_MAX_LINKS            int64
+	CONTENT_FILTER_DUPLICATE_WINDOW     string
+	CONTENT_FILTER_DUPLICATE_MIN_LENGTH int64
+
+	// Rate limit Configuration
+	RATE_LIMIT_IP_PER_MINUTE       int64
+	RATE_LIMIT_AUTH_PER_MINUTE     int64
+	RATE_LIMIT_POSTS_PER_HOUR      int64
+	RATE_LIMIT_COMMENTS_PER_MINUTE int64
+	RATE_LIMIT_LIKES_PER_MINUTE    int64
+	RATE_LIMIT_USER_PER_MINUTE     int64
+	RATE_LIMIT_REPORTS_PER_HOUR    int64
+	RATE_LIMIT_USERNAME_PER_DAY    int64
 }
 
 var Envs = initConfig()
@@ -72,10 +107,20 @@ func initConfig() Config {
 		GOOGLE_CLIENT_SECRET: getEnv("GOOGLE_CLIENT_SECRET", ""),
 		GOOGLE_REDIRECT_URI:  getEnv("GOOGLE_REDIRECT_URI", ""),
 
+		// GitHub OAuth configuration
+		GITHUB_CLIENT_ID:     getEnv("GITHUB_CLIENT_ID", ""),
+		GITHUB_CLIENT_SECRET: getEnv("GITHUB_CLIENT_SECRET", ""),
+		GITHUB_REDIRECT_URI:  getEnv("GITHUB_REDIRECT_URI", ""),
+
 		// JWT Configuration
 		JWT_SECRET:                   getEnv("JWT_SECRET", ""),
 		ACCESS_TOKEN_EXPIRE_MINUTES:  getEnvAsInt("ACCESS_TOKEN_EXPIRE_MINUTES", 1440),
 		REFRESH_TOKEN_EXPIRE_MINUTES: getEnvAsInt("REFRESH_TOKEN_EXPIRE_MINUTES", 10080),
+		JWT_KEYS_DIR:                 getEnv("JWT_KEYS_DIR", ""),
+		JWT_SIGNING_KEY_ID:           getEnv("JWT_SIGNING_KEY_ID", ""),
+
+		// Admin Configuration
+		ADMIN_EMAILS: getEnv("ADMIN_EMAILS", ""),
 
 		// Cookie Configuration
 		Secure: getEnvAsBool("Secure", true),
@@ -86,6 +131,31 @@ func initConfig() Config {
 		// MailerSend Configuration
 		MAILERSEND_API_KEY: getEnv("MAILERSEND_API_KEY", ""),
 		FROM_EMAIL:         getEnv("FROM_EMAIL", ""),
+
+		// Scheduler Configuration
+		PUBLISH_SCHEDULER_INTERVAL: getEnv("PUBLISH_SCHEDULER_INTERVAL", "1m"),
+		PURGE_SCHEDULER_INTERVAL:   getEnv("PURGE_SCHEDULER_INTERVAL", "1h"),
+
+		// Soft delete Configuration
+		RESTORE_WINDOW_DAYS: getEnvAsInt("RESTORE_WINDOW_DAYS", 30),
+
+		// Content filter Configuration
+		CONTENT_FILTER_ENABLED:              getEnvAsBool("CONTENT_FILTER_ENABLED", true),
+		CONTENT_FILTER_BLOCKED_WORDS:        getEnv("CONTENT_FILTER_BLOCKED_WORDS", ""),
+		CONTENT_FILTER_FLAGGED_WORDS:        getEnv("CONTENT_FILTER_FLAGGED_WORDS", "casino,viagra,free money,click here,crypto giveaway,work from home"),
+		CONTENT_FILTER_MAX_LINKS:            getEnvAsInt("CONTENT_FILTER_MAX_LINKS", 3),
+		CONTENT_FILTER_DUPLICATE_WINDOW:     getEnv("CONTENT_FILTER_DUPLICATE_WINDOW", "10m"),
+		CONTENT_FILTER_DUPLICATE_MIN_LENGTH: getEnvAsInt("CONTENT_FILTER_DUPLICATE_MIN_LENGTH", 30),
+
+		// Rate limit Configuration
+		RATE_LIMIT_IP_PER_MINUTE:       getEnvAsInt("RATE_LIMIT_IP_PER_MINUTE", 300),
+		RATE_LIMIT_AUTH_PER_MINUTE:     getEnvAsInt("RATE_LIMIT_AUTH_PER_MINUTE", 20),
+		RATE_LIMIT_POSTS_PER_HOUR:      getEnvAsInt("RATE_LIMIT_POSTS_PER_HOUR", 10),
+		RATE_LIMIT_COMMENTS_PER_MINUTE: getEnvAsInt("RATE_LIMIT_COMMENTS_PER_MINUTE", 10),
+		RATE_LIMIT_LIKES_PER_MINUTE:    getEnvAsInt("RATE_LIMIT_LIKES_PER_MINUTE", 60),
+		RATE_LIMIT_USER_PER_MINUTE:     getEnvAsInt("RATE_LIMIT_USER_PER_MINUTE", 120),
+		RATE_LIMIT_REPORTS_PER_HOUR:    getEnvAsInt("RATE_LIMIT_REPORTS_PER_HOUR", 20),
+		RATE_LIMIT_USERNAME_PER_DAY:    getEnvAsInt("RATE_LIMIT_USERNAME_PER_DAY", 5),
 	}
 }
 
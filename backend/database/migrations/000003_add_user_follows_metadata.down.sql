@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_follows_followee_id;
+
+ALTER TABLE user_follows DROP CONSTRAINT IF EXISTS chk_user_follows_not_self;
+ALTER TABLE user_follows DROP COLUMN IF EXISTS created_at;
\ No newline at end of file
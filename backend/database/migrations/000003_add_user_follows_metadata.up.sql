@@ -0,0 +1,7 @@
+-- Track when a follow happened so follower lists can be ordered
+ALTER TABLE user_follows ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Prevent users from following themselves
+ALTER TABLE user_follows ADD CONSTRAINT chk_user_follows_not_self CHECK (follower_id <> followee_id);
+
+CREATE INDEX idx_user_follows_followee_id ON user_follows(followee_id);
\ No newline at end of file
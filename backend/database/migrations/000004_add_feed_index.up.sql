@@ -0,0 +1,2 @@
+-- Supports keyset pagination over an author's posts in the following feed
+CREATE INDEX idx_posts_author_id_created_at_id ON posts(author_id, created_at DESC, id DESC);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_parent_id;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS is_deleted;
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
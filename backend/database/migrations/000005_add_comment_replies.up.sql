@@ -0,0 +1,7 @@
+-- Replies reference their parent comment; removing a post still cascades to all of its comments
+ALTER TABLE comments ADD COLUMN parent_id UUID REFERENCES comments(id) ON DELETE CASCADE;
+
+-- Deleted comments that still have replies are kept as placeholders so threads stay intact
+ALTER TABLE comments ADD COLUMN is_deleted BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_comments_parent_id ON comments(parent_id);
\ No newline at end of file
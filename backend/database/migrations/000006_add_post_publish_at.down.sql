@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_publish_at;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Posts with a publish_at in the future stay unpublished until the scheduler publishes them
+ALTER TABLE posts ADD COLUMN publish_at TIMESTAMPTZ;
+
+CREATE INDEX idx_posts_publish_at ON posts(publish_at) WHERE is_published = FALSE AND publish_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,15 @@
+-- Snapshot of a post's title and body for every saved version
+CREATE TABLE post_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision_number INT NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (post_id, revision_number)
+);
+
+-- Existing posts start their history from their current content
+INSERT INTO post_revisions (post_id, revision_number, title, body, created_at)
+SELECT id, 1, title, body, updated_at
+FROM posts;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS post_slug_history;
\ No newline at end of file
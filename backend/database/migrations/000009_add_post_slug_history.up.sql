@@ -0,0 +1,8 @@
+-- Slugs a post used to have, so old links keep resolving to the post
+CREATE TABLE post_slug_history (
+    slug TEXT PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_post_slug_history_post_id ON post_slug_history(post_id);
\ No newline at end of file
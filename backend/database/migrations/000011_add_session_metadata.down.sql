@@ -0,0 +1,4 @@
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS last_used_at;
\ No newline at end of file
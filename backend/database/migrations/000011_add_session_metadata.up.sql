@@ -0,0 +1,5 @@
+-- Device details shown to users so they can recognise and revoke their sessions
+ALTER TABLE sessions
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN ip_address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN last_used_at TIMESTAMPTZ NOT NULL DEFAULT now();
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- Users that only have non-Google identities are left without a google_id
+ALTER TABLE users ADD COLUMN google_id TEXT UNIQUE;
+
+UPDATE users u
+SET google_id = ui.subject
+FROM user_identities ui
+WHERE ui.user_id = u.id AND ui.provider = 'google';
+
+DROP TABLE IF EXISTS user_identities;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- A user can sign in with several OAuth providers; each linked account is one identity
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+INSERT INTO user_identities (user_id, provider, subject)
+SELECT id, 'google', google_id FROM users;
+
+ALTER TABLE users DROP COLUMN google_id;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Usernames a user used to have, so old profile links keep resolving to the user
+CREATE TABLE username_history (
+    username TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_username_history_user_id ON username_history(user_id);
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS display_name,
+    DROP COLUMN IF EXISTS website_url,
+    DROP COLUMN IF EXISTS github_url,
+    DROP COLUMN IF EXISTS twitter_url,
+    DROP COLUMN IF EXISTS linkedin_url,
+    DROP COLUMN IF EXISTS location,
+    DROP COLUMN IF EXISTS custom_avatar_url;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- Profile details authors fill in themselves. custom_avatar_url overrides the avatar from the login provider.
+ALTER TABLE users
+    ADD COLUMN display_name TEXT,
+    ADD COLUMN website_url TEXT,
+    ADD COLUMN github_url TEXT,
+    ADD COLUMN twitter_url TEXT,
+    ADD COLUMN linkedin_url TEXT,
+    ADD COLUMN location TEXT,
+    ADD COLUMN custom_avatar_url TEXT;
\ No newline at end of file
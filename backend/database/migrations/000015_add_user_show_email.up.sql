@@ -0,0 +1,2 @@
+-- Emails stay private unless the user opts in to showing theirs on their profile
+ALTER TABLE users ADD COLUMN show_email BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
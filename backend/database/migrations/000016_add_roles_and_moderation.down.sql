@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS moderation_actions;
+ALTER TABLE comments DROP COLUMN IF EXISTS is_hidden;
+ALTER TABLE posts DROP COLUMN IF EXISTS is_hidden;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
\ No newline at end of file
@@ -0,0 +1,22 @@
+-- Suspended users can't sign in or use authenticated endpoints until the suspension is lifted
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMPTZ;
+
+-- Reports flag posts, comments and users for review by moderators
+CREATE TABLE reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment', 'user')),
+    target_id UUID NOT NULL,
+    reason TEXT NOT NULL CHECK (reason IN ('spam', 'harassment', 'hate_speech', 'misinformation', 'inappropriate', 'other')),
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+    resolution TEXT CHECK (resolution IN ('dismiss', 'hide_content', 'suspend_author')),
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- a reader can only have one open report per target
+CREATE UNIQUE INDEX idx_reports_open_reporter_target ON reports(reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX idx_reports_status_created_at ON reports(status, created_at);
+CREATE INDEX idx_reports_target ON reports(target_type, target_id);
@@ -0,0 +1,15 @@
+-- Dropping deleted_at would bring soft-deleted rows back as live ones, and deleting them
+-- here would throw away content that can still be restored. Refuse until they are gone,
+-- either purged once the restore window passes or deleted by hand if losing them is intended.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users WHERE deleted_at IS NOT NULL)
+        OR EXISTS (SELECT 1 FROM posts WHERE deleted_at IS NOT NULL)
+        OR EXISTS (SELECT 1 FROM comments WHERE deleted_at IS NOT NULL) THEN
+        RAISE EXCEPTION 'soft-deleted users, posts or comments still exist; purge or delete them before rolling back';
+    END IF;
+END $$;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,17 @@
+-- Blocked users can't comment on or like the blocker's posts
+CREATE TABLE user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CONSTRAINT chk_user_blocks_not_self CHECK (blocker_id <> blocked_id)
+);
+
+-- Muted authors are hidden from the muter's listings and comment threads
+CREATE TABLE user_mutes (
+    muter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (muter_id, muted_id),
+    CONSTRAINT chk_user_mutes_not_self CHECK (muter_id <> muted_id)
+);
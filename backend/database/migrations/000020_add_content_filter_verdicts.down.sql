@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_comments_user_id_created_at;
+DROP TABLE IF EXISTS content_filter_verdicts;
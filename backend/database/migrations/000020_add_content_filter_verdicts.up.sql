@@ -0,0 +1,21 @@
+-- Posts and comments that the content filter rejected or held for moderation,
+-- kept so moderators can review the decision
+CREATE TABLE content_filter_verdicts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment')),
+    -- the held post or comment; rejected content is never saved so it has none
+    target_id UUID,
+    action TEXT NOT NULL CHECK (action IN ('hold', 'reject')),
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    body TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'confirmed')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_content_filter_verdicts_status_created_at ON content_filter_verdicts(status, created_at);
+
+-- duplicate detection looks up an author's recent comments
+CREATE INDEX idx_comments_user_id_created_at ON comments(user_id, created_at);
@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS idx_posts_author_id_created_at_id ON posts(author_id, created_at DESC, id DESC);
+
+DROP INDEX IF EXISTS idx_posts_author_id_published_at_id;
+DROP INDEX IF EXISTS idx_posts_published_at_id;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS published_at;
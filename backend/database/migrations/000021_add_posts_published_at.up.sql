@@ -0,0 +1,11 @@
+-- Listings are ordered by when a post went public, so scheduled posts and older drafts
+-- show up as new when they are published instead of at their creation time
+ALTER TABLE posts ADD COLUMN published_at TIMESTAMPTZ;
+
+UPDATE posts SET published_at = created_at WHERE is_published = TRUE;
+
+CREATE INDEX idx_posts_published_at_id ON posts(published_at DESC, id DESC) WHERE is_published = TRUE;
+CREATE INDEX idx_posts_author_id_published_at_id ON posts(author_id, published_at DESC, id DESC);
+
+-- The feed now pages by published_at, which leaves the created_at feed index from 000004 unused
+DROP INDEX IF EXISTS idx_posts_author_id_created_at_id;
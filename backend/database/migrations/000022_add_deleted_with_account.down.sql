@@ -0,0 +1,2 @@
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_with_account;
+ALTER TABLE posts DROP COLUMN IF EXISTS deleted_with_account;
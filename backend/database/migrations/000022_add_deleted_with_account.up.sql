@@ -0,0 +1,12 @@
+-- Marks posts and comments removed along with their author's account, so reactivating the
+-- account restores exactly those and not ones deleted separately beforehand
+ALTER TABLE posts ADD COLUMN deleted_with_account BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN deleted_with_account BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE posts p SET deleted_with_account = TRUE
+FROM users u
+WHERE u.id = p.author_id AND p.deleted_at = u.deleted_at;
+
+UPDATE comments c SET deleted_with_account = TRUE
+FROM users u
+WHERE u.id = c.user_id AND c.deleted_at = u.deleted_at;
@@ -0,0 +1,12 @@
+-- Comments whose author was purged can't be kept once an author is required again, and
+-- deleting them would also delete the replies below them, so refuse while any exist
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM comments WHERE user_id IS NULL) THEN
+        RAISE EXCEPTION 'comments without an author still exist; delete them before rolling back';
+    END IF;
+END $$;
+
+ALTER TABLE comments DROP CONSTRAINT comments_user_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE comments ALTER COLUMN user_id SET NOT NULL;
@@ -0,0 +1,5 @@
+-- Placeholders left by a deleted account hold replies from other users, so purging the
+-- account clears their author instead of cascading the delete down the thread
+ALTER TABLE comments ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE comments DROP CONSTRAINT comments_user_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,11 @@
+-- Records who deleted a post, so authors can only restore posts they deleted themselves and
+-- not ones a moderator removed
+ALTER TABLE posts ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE posts p SET deleted_by = p.author_id
+WHERE p.deleted_at IS NOT NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM moderation_actions m
+    WHERE m.action = 'delete' AND m.target_type = 'post' AND m.target_id = p.id AND m.created_at >= p.deleted_at
+);
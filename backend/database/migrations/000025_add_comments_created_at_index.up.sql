@@ -0,0 +1,2 @@
+-- duplicate detection looks up recent comments from every author
+CREATE INDEX idx_comments_created_at ON comments(created_at);
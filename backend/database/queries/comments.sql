@@ -1,13 +1,70 @@
 -- name: GetCommentsByPostSlug :many
+-- Returns a page of top-level comments; replies are loaded with GetRepliesByRootCommentIDs.
 SELECT c.*
 FROM comments c
 JOIN posts p ON p.id = c.post_id
-WHERE p.slug = $1
-ORDER BY c.created_at DESC;
+WHERE p.slug = sqlc.arg('slug')
+AND c.parent_id IS NULL
+AND c.deleted_at IS NULL
+AND p.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = c.user_id
+)
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::text = 'most_liked' THEN (
+        SELECT COUNT(*) FROM comment_likes cl WHERE cl.comment_id = c.id
+    ) END DESC,
+    CASE WHEN sqlc.arg('sort')::text = 'oldest' THEN c.created_at END ASC,
+    c.created_at DESC,
+    c.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountCommentsByPostSlug :one
+SELECT COUNT(*)::bigint AS total
+FROM comments c
+JOIN posts p ON p.id = c.post_id
+WHERE p.slug = sqlc.arg('slug')
+AND c.parent_id IS NULL
+AND c.deleted_at IS NULL
+AND p.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = c.user_id
+);
+
+-- name: GetRepliesByRootCommentIDs :many
+-- Replies by users the viewer muted are left out along with the replies beneath them.
+WITH RECURSIVE thread AS (
+    SELECT r.id
+    FROM comments r
+    WHERE r.parent_id = ANY(sqlc.arg('root_ids')::uuid[])
+    AND r.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_mutes m
+        WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = r.user_id
+    )
+    UNION ALL
+    SELECT r.id
+    FROM comments r
+    JOIN thread t ON r.parent_id = t.id
+    WHERE r.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_mutes m
+        WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = r.user_id
+    )
+)
+SELECT c.*
+FROM comments c
+WHERE c.id IN (SELECT id FROM thread);
 
 -- name: CreateComment :one
-INSERT INTO comments (post_id, user_id, body)
-VALUES ($1, $2, $3)
+INSERT INTO comments (post_id, user_id, body, parent_id)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: UpdateComment :one
@@ -16,8 +73,104 @@ SET body = $2, updated_at = now()
 WHERE id = $1
 RETURNING *;
 
--- name: DeleteComment :exec
-DELETE FROM comments WHERE id = $1;
-
 -- name: GetCommentByID :one
-SELECT * FROM comments WHERE id = $1;
+SELECT * FROM comments WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: CountRepliesByCommentID :one
+SELECT COUNT(*)::bigint AS reply_count
+FROM comments
+WHERE parent_id = $1 AND deleted_at IS NULL;
+
+-- name: CountRecentDuplicateComments :one
+-- Bodies are compared lowercased with whitespace collapsed; body must be normalized the same way.
+SELECT
+    COUNT(*) FILTER (WHERE user_id = sqlc.arg('author_id'))::bigint AS same_author_count,
+    COUNT(*) FILTER (WHERE user_id IS DISTINCT FROM sqlc.arg('author_id'))::bigint AS other_author_count
+FROM comments
+WHERE lower(btrim(regexp_replace(body, '\s+', ' ', 'g'))) = sqlc.arg('body')
+AND created_at > sqlc.arg('created_after')
+AND (sqlc.narg('exclude_id')::uuid IS NULL OR id <> sqlc.narg('exclude_id')::uuid)
+AND deleted_at IS NULL;
+
+-- name: TombstoneComment :one
+UPDATE comments
+SET body = '', is_deleted = TRUE, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetCommentLike :one
+SELECT *
+FROM comment_likes
+WHERE comment_id = $1 AND user_id = $2;
+
+-- name: CreateCommentLike :one
+INSERT INTO comment_likes (comment_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (comment_id, user_id) DO NOTHING
+RETURNING *;
+
+-- name: DeleteCommentLike :exec
+DELETE FROM comment_likes
+WHERE comment_id = $1 AND user_id = $2;
+
+-- name: GetLikeCountsByCommentIDs :many
+SELECT
+    comment_id,
+    COUNT(*)::bigint AS like_count
+FROM comment_likes
+WHERE comment_id = ANY($1::uuid[])
+GROUP BY comment_id;
+
+-- name: GetUserLikedCommentIDs :many
+SELECT comment_id
+FROM comment_likes
+WHERE user_id = $1
+AND comment_id = ANY($2::uuid[]);
+
+-- name: SetCommentHidden :one
+UPDATE comments
+SET is_hidden = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: SoftDeleteComment :exec
+UPDATE comments
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: TombstoneCommentsWithRepliesByUserID :exec
+-- Replaces the user's comments that have a reply from someone else anywhere below them
+-- with placeholders, so deleting the account keeps those threads intact.
+WITH RECURSIVE thread AS (
+    SELECT c.id AS root_id, c.id
+    FROM comments c
+    WHERE c.user_id = $1 AND c.is_deleted = FALSE AND c.deleted_at IS NULL
+    UNION ALL
+    SELECT t.root_id, r.id
+    FROM comments r
+    JOIN thread t ON r.parent_id = t.id
+    WHERE r.deleted_at IS NULL
+)
+UPDATE comments
+SET body = '', is_deleted = TRUE, updated_at = now()
+WHERE id IN (
+    SELECT t.root_id
+    FROM thread t
+    JOIN comments r ON r.id = t.id
+    WHERE r.user_id IS DISTINCT FROM $1 AND r.is_deleted = FALSE
+);
+
+-- name: SoftDeleteCommentsByUserID :exec
+-- Placeholders are skipped, since removing one would also hide the replies below it.
+UPDATE comments
+SET deleted_at = now(), deleted_with_account = TRUE
+WHERE user_id = $1 AND is_deleted = FALSE AND deleted_at IS NULL;
+
+-- name: RestoreCommentsByUserID :exec
+UPDATE comments
+SET deleted_at = NULL, deleted_with_account = FALSE
+WHERE user_id = $1 AND deleted_with_account = TRUE;
+
+-- name: PurgeDeletedComments :execrows
+DELETE FROM comments
+WHERE deleted_at < sqlc.arg('deleted_before');
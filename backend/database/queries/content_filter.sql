@@ -0,0 +1,22 @@
+-- name: CreateContentFilterVerdict :one
+INSERT INTO content_filter_verdicts (author_id, target_type, target_id, action, reasons, body)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetContentFilterVerdictByID :one
+SELECT * FROM content_filter_verdicts
+WHERE id = $1;
+
+-- name: GetContentFilterVerdicts :many
+SELECT *
+FROM content_filter_verdicts
+WHERE (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+AND (sqlc.narg('action')::text IS NULL OR action = sqlc.narg('action')::text)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ReviewContentFilterVerdict :one
+UPDATE content_filter_verdicts
+SET status = $2, reviewed_by = $3, reviewed_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,11 @@
+-- name: GetUserByIdentity :one
+-- Includes deleted accounts, so signing in again can reactivate them.
+SELECT u.*
+FROM user_identities ui
+JOIN users u ON u.id = ui.user_id
+WHERE ui.provider = $1 AND ui.subject = $2;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, subject)
+VALUES ($1, $2, $3)
+RETURNING *;
@@ -0,0 +1,9 @@
+-- name: CreateModerationAction :exec
+INSERT INTO moderation_actions (moderator_id, action, target_type, target_id, reason)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetModerationActions :many
+SELECT *
+FROM moderation_actions
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -2,16 +2,57 @@
 SELECT p.*
 FROM posts p
 JOIN users u ON u.id = p.author_id
-WHERE u.username = $1
-ORDER BY p.created_at DESC;
+WHERE u.username = sqlc.arg('username')
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = sqlc.narg('viewer_id'))
+AND (
+    sqlc.narg('cursor_published_at')::timestamptz IS NULL
+    OR (COALESCE(p.published_at, p.created_at), p.id) < (sqlc.narg('cursor_published_at')::timestamptz, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY COALESCE(p.published_at, p.created_at) DESC, p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: GetPostsLikedByUsername :many
 SELECT p.*
 FROM post_likes pl
 JOIN users u ON u.id = pl.user_id
 JOIN posts p ON p.id = pl.post_id
-WHERE u.username = $1
-ORDER BY p.created_at DESC;
+WHERE u.username = sqlc.arg('username')
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = sqlc.narg('viewer_id'))
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = p.author_id
+)
+AND (
+    sqlc.narg('cursor_published_at')::timestamptz IS NULL
+    OR (COALESCE(p.published_at, p.created_at), p.id) < (sqlc.narg('cursor_published_at')::timestamptz, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY COALESCE(p.published_at, p.created_at) DESC, p.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountPostsByUsername :one
+SELECT COUNT(*)::bigint AS total
+FROM posts p
+JOIN users u ON u.id = p.author_id
+WHERE u.username = sqlc.arg('username')
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = sqlc.narg('viewer_id'));
+
+-- name: CountPostsLikedByUsername :one
+SELECT COUNT(*)::bigint AS total
+FROM post_likes pl
+JOIN users u ON u.id = pl.user_id
+JOIN posts p ON p.id = pl.post_id
+WHERE u.username = sqlc.arg('username')
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = sqlc.narg('viewer_id'))
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = p.author_id
+);
 
 -- name: GetCategoriesByPostIDs :many
 SELECT
@@ -38,6 +79,9 @@ SELECT
     COUNT(*)::bigint AS comment_count
 FROM comments
 WHERE post_id = ANY($1::uuid[])
+AND is_deleted = FALSE
+AND is_hidden = FALSE
+AND deleted_at IS NULL
 GROUP BY post_id;
 
 -- name: GetUserLikedPostIDs :many
@@ -47,24 +91,45 @@ WHERE user_id = $1
 AND post_id = ANY($2::uuid[]);
 
 -- name: GetPostBySearchAndCategoryPaginated :many
-SELECT DISTINCT p.*
+SELECT
+    sqlc.embed(p),
+    COALESCE(ts_rank(p.search_vector, websearch_to_tsquery('english', sqlc.narg('search')::text)), 0)::real AS rank,
+    COALESCE(ts_headline('english', p.body, websearch_to_tsquery('english', sqlc.narg('search')::text), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10'), '')::text AS snippet
 FROM posts p
-LEFT JOIN post_categories pc ON pc.post_id = p.id
-LEFT JOIN categories c ON c.id = pc.category_id
-WHERE (sqlc.narg('category_slug')::text IS NULL OR sqlc.narg('category_slug') = '' OR c.slug = sqlc.narg('category_slug'))
-AND (sqlc.narg('search')::text IS NULL OR sqlc.narg('search') = '' OR p.title ILIKE '%' || sqlc.narg('search') || '%')
-ORDER BY p.created_at DESC
+WHERE p.is_published = TRUE
+AND p.is_hidden = FALSE
+AND p.deleted_at IS NULL
+AND (sqlc.narg('category_slug')::text IS NULL OR EXISTS (
+    SELECT 1
+    FROM post_categories pc
+    JOIN categories c ON c.id = pc.category_id
+    WHERE pc.post_id = p.id AND c.slug = sqlc.narg('category_slug')
+))
+AND (sqlc.narg('search')::text IS NULL OR p.search_vector @@ websearch_to_tsquery('english', sqlc.narg('search')::text))
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = sqlc.narg('viewer_id') AND m.muted_id = p.author_id
+)
+AND (
+    sqlc.narg('cursor_published_at')::timestamptz IS NULL
+    OR (p.published_at, p.id) < (sqlc.narg('cursor_published_at')::timestamptz, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY
+    CASE WHEN sqlc.arg('sort')::text = 'relevance' THEN ts_rank(p.search_vector, websearch_to_tsquery('english', sqlc.narg('search')::text)) END DESC,
+    p.published_at DESC,
+    p.id DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: GetPostBySlug :one
 SELECT * 
 FROM posts 
-WHERE slug = $1;
+WHERE slug = $1 AND deleted_at IS NULL;
 
 -- name: GetPostByID :one
 SELECT *
 FROM posts
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: CreatePostLike :one
 INSERT INTO post_likes (post_id, user_id)
@@ -82,16 +147,148 @@ FROM post_likes
 WHERE post_id = $1 AND user_id = $2;
 
 -- name: CreatePost :one
-INSERT INTO posts (title, body, slug, author_id, is_published)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO posts (title, body, slug, author_id, is_published, publish_at, published_at)
+VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $5::boolean THEN NOW() END)
 RETURNING *;
 
 -- name: UpdatePost :one
 UPDATE posts
-SET title = $2, body = $3, slug = $4, is_published = $5, updated_at = NOW()
+SET title = $2, body = $3, slug = $4, is_published = $5, publish_at = $6,
+    published_at = CASE WHEN $5::boolean THEN COALESCE(published_at, NOW()) ELSE published_at END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetPostPublished :one
+UPDATE posts
+SET is_published = $2, publish_at = NULL,
+    published_at = CASE WHEN $2::boolean THEN COALESCE(published_at, NOW()) ELSE published_at END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetDraftPostsByAuthorID :many
+SELECT *
+FROM posts
+WHERE author_id = $1
+AND is_published = FALSE
+AND deleted_at IS NULL
+ORDER BY updated_at DESC;
+
+-- name: GetFeedPostsByUserID :many
+SELECT p.*
+FROM posts p
+JOIN user_follows uf ON uf.followee_id = p.author_id
+WHERE uf.follower_id = sqlc.arg('user_id')
+AND p.is_published = TRUE
+AND p.is_hidden = FALSE
+AND p.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = sqlc.arg('user_id') AND m.muted_id = p.author_id
+)
+AND (
+    sqlc.narg('cursor_published_at')::timestamptz IS NULL
+    OR (p.published_at, p.id) < (sqlc.narg('cursor_published_at')::timestamptz, sqlc.narg('cursor_id')::uuid)
+)
+ORDER BY p.published_at DESC, p.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: PublishDuePosts :many
+UPDATE posts
+SET is_published = TRUE, publish_at = NULL, published_at = COALESCE(published_at, NOW()), updated_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM posts
+    WHERE is_published = FALSE
+    AND publish_at IS NOT NULL
+    AND publish_at <= NOW()
+    AND deleted_at IS NULL
+    ORDER BY publish_at
+    LIMIT sqlc.arg('batch_size')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdatePostContent :one
+UPDATE posts
+SET title = $2, body = $3, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
--- name: DeletePost :exec
+-- name: CreatePostRevision :one
+INSERT INTO post_revisions (post_id, revision_number, title, body)
+SELECT sqlc.arg('post_id')::uuid, COALESCE(MAX(revision_number), 0) + 1, sqlc.arg('title')::text, sqlc.arg('body')::text
+FROM post_revisions
+WHERE post_id = sqlc.arg('post_id')::uuid
+RETURNING *;
+
+-- name: GetPostRevisionsByPostID :many
+SELECT *
+FROM post_revisions
+WHERE post_id = $1
+ORDER BY revision_number DESC;
+
+-- name: GetPostRevision :one
+SELECT *
+FROM post_revisions
+WHERE post_id = $1 AND revision_number = $2;
+
+-- name: IsSlugTaken :one
+SELECT (
+    EXISTS (SELECT 1 FROM posts WHERE slug = sqlc.arg('slug') AND id IS DISTINCT FROM sqlc.narg('post_id'))
+    OR EXISTS (SELECT 1 FROM post_slug_history WHERE slug = sqlc.arg('slug') AND post_id IS DISTINCT FROM sqlc.narg('post_id'))
+)::boolean AS is_taken;
+
+-- name: CreatePostSlugHistory :exec
+INSERT INTO post_slug_history (slug, post_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO NOTHING;
+
+-- name: DeletePostSlugHistory :exec
+DELETE FROM post_slug_history
+WHERE slug = $1 AND post_id = $2;
+
+-- name: GetCurrentSlugByOldSlug :one
+SELECT p.slug AS current_slug
+FROM post_slug_history h
+JOIN posts p ON p.id = h.post_id
+WHERE h.slug = $1 AND p.deleted_at IS NULL;
+
+-- name: SetPostHidden :one
+UPDATE posts
+SET is_hidden = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: SoftDeletePost :exec
+UPDATE posts
+SET deleted_at = now(), deleted_by = $2
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetDeletedPostByID :one
+SELECT *
+FROM posts
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: RestorePost :one
+-- Only posts the author deleted themselves can be restored.
+UPDATE posts
+SET deleted_at = NULL, deleted_by = NULL
+WHERE id = $1 AND author_id = $2 AND deleted_by = author_id AND deleted_at >= sqlc.arg('deleted_after')
+RETURNING *;
+
+-- name: SoftDeletePostsByAuthorID :exec
+UPDATE posts
+SET deleted_at = now(), deleted_by = author_id, deleted_with_account = TRUE
+WHERE author_id = $1 AND deleted_at IS NULL;
+
+-- name: RestorePostsByAuthorID :exec
+UPDATE posts
+SET deleted_at = NULL, deleted_by = NULL, deleted_with_account = FALSE
+WHERE author_id = $1 AND deleted_with_account = TRUE;
+
+-- name: PurgeDeletedPosts :execrows
 DELETE FROM posts
-WHERE id = $1;
\ No newline at end of file
+WHERE deleted_at < sqlc.arg('deleted_before');
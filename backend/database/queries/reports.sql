@@ -0,0 +1,27 @@
+-- name: CreateReport :one
+INSERT INTO reports (reporter_id, target_type, target_id, reason, details)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetReportByID :one
+SELECT * FROM reports
+WHERE id = $1;
+
+-- name: GetReportByIDForUpdate :one
+-- Locks the report so only one moderator can resolve it.
+SELECT * FROM reports
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetReports :many
+SELECT *
+FROM reports
+WHERE (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+AND (sqlc.narg('target_type')::text IS NULL OR target_type = sqlc.narg('target_type')::text)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ResolveReportsByTarget :exec
+UPDATE reports
+SET status = $3, resolution = $4, resolved_by = $5, resolved_at = now()
+WHERE target_type = $1 AND target_id = $2 AND status = 'open';
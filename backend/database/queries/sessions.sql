@@ -0,0 +1,56 @@
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, user_agent, ip_address)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetSessionByID :one
+SELECT * FROM sessions WHERE id = $1;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: TouchSession :exec
+-- Throttled so authenticated requests don't write on every call.
+UPDATE sessions
+SET last_used_at = now()
+WHERE id = $1 AND last_used_at < now() - INTERVAL '1 minute';
+
+-- name: GetActiveSessionsByUserID :many
+-- A session is active until revoked or until its latest refresh token expires.
+SELECT s.*
+FROM sessions s
+WHERE s.user_id = $1
+AND s.revoked_at IS NULL
+AND EXISTS (
+    SELECT 1 FROM refresh_tokens rt
+    WHERE rt.session_id = s.id AND rt.used_at IS NULL AND rt.expires_at > now()
+)
+ORDER BY s.last_used_at DESC;
+
+-- name: RevokeSessionsByUserID :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (session_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetRefreshTokenByHash :one
+SELECT *
+FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_tokens
+SET used_at = now()
+WHERE id = $1;
@@ -1,30 +1,188 @@
 -- name: GetUserByID :one
-SELECT id, google_id, username, email, description, avatar_url, created_at, updated_at FROM users 
-WHERE id = $1;
+SELECT id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at FROM users 
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetUsersByIDs :many
 SELECT *
 FROM users
-WHERE id = ANY($1::uuid[]);
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL;
 
--- name: GetUserByGoogleID :one
-SELECT id, google_id, username, email, description, avatar_url, created_at, updated_at FROM users 
-WHERE google_id = $1;
+-- name: GetUserByEmail :one
+-- Includes deleted accounts, so signing in again can reactivate them.
+SELECT id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at FROM users 
+WHERE lower(email) = lower($1);
 
 -- name: GetUserByUsername :one
-SELECT id, google_id, username, email, description, avatar_url, created_at, updated_at FROM users 
-WHERE username = $1;
+SELECT id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at FROM users 
+WHERE username = $1 AND deleted_at IS NULL;
 
 -- name: CreateUser :one
-INSERT INTO users (google_id, username, email, avatar_url)
-VALUES ($1, $2, $3, $4)
-RETURNING id, google_id, username, email, description, avatar_url, created_at, updated_at;
+INSERT INTO users (username, email, avatar_url)
+VALUES ($1, $2, $3)
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at;
 
 -- name: UpdateUser :one
 UPDATE users
-SET description = $2, updated_at = now()
+SET description = $2,
+    display_name = $3,
+    website_url = $4,
+    github_url = $5,
+    twitter_url = $6,
+    linkedin_url = $7,
+    location = $8,
+    custom_avatar_url = $9,
+    show_email = $10,
+    updated_at = now()
 WHERE id = $1
-RETURNING id, google_id, username, email, description, avatar_url, created_at, updated_at;
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at;
 
--- name: DeleteUserByID :exec
-DELETE FROM users WHERE id = $1;
\ No newline at end of file
+-- name: IsUsernameTaken :one
+SELECT (
+    EXISTS (SELECT 1 FROM users WHERE lower(username) = lower(sqlc.arg('username')) AND id IS DISTINCT FROM sqlc.narg('user_id'))
+    OR EXISTS (SELECT 1 FROM username_history WHERE lower(username) = lower(sqlc.arg('username')) AND user_id IS DISTINCT FROM sqlc.narg('user_id'))
+)::boolean AS is_taken;
+
+-- name: UpdateUsername :one
+UPDATE users
+SET username = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at;
+
+-- name: SuspendUser :one
+UPDATE users
+SET suspended_at = now()
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at;
+
+-- name: UnsuspendUser :one
+UPDATE users
+SET suspended_at = NULL
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at;
+
+-- name: HasAdmin :one
+SELECT EXISTS (
+    SELECT 1 FROM users WHERE role = 'admin' AND deleted_at IS NULL
+) AS has_admin;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at;
+
+-- name: CreateUsernameHistory :exec
+INSERT INTO username_history (username, user_id)
+VALUES ($1, $2)
+ON CONFLICT (username) DO NOTHING;
+
+-- name: DeleteUsernameHistory :exec
+DELETE FROM username_history
+WHERE username = $1 AND user_id = $2;
+
+-- name: GetCurrentUsernameByOldUsername :one
+SELECT u.username AS current_username
+FROM username_history h
+JOIN users u ON u.id = h.user_id
+WHERE h.username = $1 AND u.deleted_at IS NULL;
+
+-- name: SoftDeleteUser :exec
+UPDATE users
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at < sqlc.arg('deleted_before');
+
+-- name: CountVisibleContentByUserID :one
+SELECT (
+    (SELECT COUNT(*) FROM posts
+     WHERE author_id = $1 AND is_published AND NOT is_hidden AND deleted_at IS NULL)
+    + (SELECT COUNT(*) FROM comments
+       WHERE user_id = $1 AND NOT is_deleted AND NOT is_hidden AND deleted_at IS NULL)
+)::bigint AS content_count;
+
+-- name: FollowUser :exec
+INSERT INTO user_follows (follower_id, followee_id)
+VALUES ($1, $2)
+ON CONFLICT (follower_id, followee_id) DO NOTHING;
+
+-- name: UnfollowUser :exec
+DELETE FROM user_follows
+WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: IsFollowingUser :one
+SELECT EXISTS (
+    SELECT 1 FROM user_follows
+    WHERE follower_id = $1 AND followee_id = $2
+) AS is_following;
+
+-- name: BlockUser :exec
+INSERT INTO user_blocks (blocker_id, blocked_id)
+VALUES ($1, $2)
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+
+-- name: UnblockUser :exec
+DELETE FROM user_blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: IsBlockedByUser :one
+SELECT EXISTS (
+    SELECT 1 FROM user_blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+) AS is_blocked;
+
+-- name: MuteUser :exec
+INSERT INTO user_mutes (muter_id, muted_id)
+VALUES ($1, $2)
+ON CONFLICT (muter_id, muted_id) DO NOTHING;
+
+-- name: UnmuteUser :exec
+DELETE FROM user_mutes
+WHERE muter_id = $1 AND muted_id = $2;
+
+-- name: GetFollowCountsByUserID :one
+SELECT
+    (SELECT COUNT(*) FROM user_follows uf JOIN users u ON u.id = uf.follower_id
+     WHERE uf.followee_id = sqlc.arg('user_id') AND u.deleted_at IS NULL)::bigint AS follower_count,
+    (SELECT COUNT(*) FROM user_follows uf JOIN users u ON u.id = uf.followee_id
+     WHERE uf.follower_id = sqlc.arg('user_id') AND u.deleted_at IS NULL)::bigint AS following_count;
+
+-- name: CountFollowersByUserID :one
+SELECT COUNT(*)::bigint AS total
+FROM user_follows uf
+JOIN users u ON u.id = uf.follower_id
+WHERE uf.followee_id = sqlc.arg('user_id')
+AND u.deleted_at IS NULL;
+
+-- name: CountFollowingByUserID :one
+SELECT COUNT(*)::bigint AS total
+FROM user_follows uf
+JOIN users u ON u.id = uf.followee_id
+WHERE uf.follower_id = sqlc.arg('user_id')
+AND u.deleted_at IS NULL;
+
+-- name: GetFollowersByUserID :many
+SELECT u.*
+FROM user_follows uf
+JOIN users u ON u.id = uf.follower_id
+WHERE uf.followee_id = sqlc.arg('user_id')
+AND u.deleted_at IS NULL
+ORDER BY uf.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetFollowingByUserID :many
+SELECT u.*
+FROM user_follows uf
+JOIN users u ON u.id = uf.followee_id
+WHERE uf.follower_id = sqlc.arg('user_id')
+AND u.deleted_at IS NULL
+ORDER BY uf.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -11,20 +11,101 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countCommentsByPostSlug = `-- name: CountCommentsByPostSlug :one
+SELECT COUNT(*)::bigint AS total
+FROM comments c
+JOIN posts p ON p.id = c.post_id
+WHERE p.slug = $1
+AND c.parent_id IS NULL
+AND c.deleted_at IS NULL
+AND p.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = $2 AND m.muted_id = c.user_id
+)
+`
+
+type CountCommentsByPostSlugParams struct {
+	Slug     string      `json:"slug"`
+	ViewerID pgtype.UUID `json:"viewer_id"`
+}
+
+func (q *Queries) CountCommentsByPostSlug(ctx context.Context, arg CountCommentsByPostSlugParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countCommentsByPostSlug, arg.Slug, arg.ViewerID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const countRecentDuplicateComments = `-- name: CountRecentDuplicateComments :one
+SELECT
+    COUNT(*) FILTER (WHERE user_id = $1)::bigint AS same_author_count,
+    COUNT(*) FILTER (WHERE user_id IS DISTINCT FROM $1)::bigint AS other_author_count
+FROM comments
+WHERE lower(btrim(regexp_replace(body, '\s+', ' ', 'g'))) = $2
+AND created_at > $3
+AND ($4::uuid IS NULL OR id <> $4::uuid)
+AND deleted_at IS NULL
+`
+
+type CountRecentDuplicateCommentsParams struct {
+	AuthorID     pgtype.UUID        `json:"author_id"`
+	Body         string             `json:"body"`
+	CreatedAfter pgtype.Timestamptz `json:"created_after"`
+	ExcludeID    pgtype.UUID        `json:"exclude_id"`
+}
+
+type CountRecentDuplicateCommentsRow struct {
+	SameAuthorCount  int64 `json:"same_author_count"`
+	OtherAuthorCount int64 `json:"other_author_count"`
+}
+
+// Bodies are compared lowercased with whitespace collapsed; body must be normalized the same way.
+func (q *Queries) CountRecentDuplicateComments(ctx context.Context, arg CountRecentDuplicateCommentsParams) (CountRecentDuplicateCommentsRow, error) {
+	row := q.db.QueryRow(ctx, countRecentDuplicateComments,
+		arg.AuthorID,
+		arg.Body,
+		arg.CreatedAfter,
+		arg.ExcludeID,
+	)
+	var i CountRecentDuplicateCommentsRow
+	err := row.Scan(&i.SameAuthorCount, &i.OtherAuthorCount)
+	return i, err
+}
+
+const countRepliesByCommentID = `-- name: CountRepliesByCommentID :one
+SELECT COUNT(*)::bigint AS reply_count
+FROM comments
+WHERE parent_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) CountRepliesByCommentID(ctx context.Context, parentID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countRepliesByCommentID, parentID)
+	var reply_count int64
+	err := row.Scan(&reply_count)
+	return reply_count, err
+}
+
 const createComment = `-- name: CreateComment :one
-INSERT INTO comments (post_id, user_id, body)
-VALUES ($1, $2, $3)
-RETURNING id, post_id, user_id, body, created_at, updated_at
+INSERT INTO comments (post_id, user_id, body, parent_id)
+VALUES ($1, $2, $3, $4)
+RETURNING id, post_id, user_id, body, created_at, updated_at, parent_id, is_deleted, is_hidden, deleted_at, deleted_with_account
 `
 
 type CreateCommentParams struct {
-	PostID pgtype.UUID `json:"post_id"`
-	UserID pgtype.UUID `json:"user_id"`
-	Body   string      `json:"body"`
+	PostID   pgtype.UUID `json:"post_id"`
+	UserID   pgtype.UUID `json:"user_id"`
+	Body     string      `json:"body"`
+	ParentID pgtype.UUID `json:"parent_id"`
 }
 
 func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error) {
-	row := q.db.QueryRow(ctx, createComment, arg.PostID, arg.UserID, arg.Body)
+	row := q.db.QueryRow(ctx, createComment,
+		arg.PostID,
+		arg.Body,
+		arg.ParentID,
+	)
 	var i Comment
 	err := row.Scan(
 		&i.ID,
@@ -33,21 +114,51 @@ func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (C
 		&i.Body,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
+		&i.IsDeleted,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.DeletedWithAccount,
 	)
 	return i, err
 }
 
-const deleteComment = `-- name: DeleteComment :exec
-DELETE FROM comments WHERE id = $1
+const createCommentLike = `-- name: CreateCommentLike :one
+INSERT INTO comment_likes (comment_id, user_id)
+VALUES ($1, $2)
+ON CONFLICT (comment_id, user_id) DO NOTHING
+RETURNING id, comment_id, user_id
 `
 
-func (q *Queries) DeleteComment(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteComment, id)
+type CreateCommentLikeParams struct {
+	CommentID pgtype.UUID `json:"comment_id"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) CreateCommentLike(ctx context.Context, arg CreateCommentLikeParams) (CommentLike, error) {
+	row := q.db.QueryRow(ctx, createCommentLike, arg.CommentID, arg.UserID)
+	var i CommentLike
+	err := row.Scan(&i.ID, &i.CommentID, &i.UserID)
+	return i, err
+}
+
+const deleteCommentLike = `-- name: DeleteCommentLike :exec
+DELETE FROM comment_likes
+WHERE comment_id = $1 AND user_id = $2
+`
+
+type DeleteCommentLikeParams struct {
+	CommentID pgtype.UUID `json:"comment_id"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteCommentLike(ctx context.Context, arg DeleteCommentLikeParams) error {
+	_, err := q.db.Exec(ctx, deleteCommentLike, arg.CommentID, arg.UserID)
 	return err
 }
 
 const getCommentByID = `-- name: GetCommentByID :one
-SELECT id, post_id, user_id, body, created_at, updated_at FROM comments WHERE id = $1
+SELECT id, post_id, user_id, body, created_at, updated_at, parent_id, is_deleted, is_hidden, deleted_at, deleted_with_account FROM comments WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetCommentByID(ctx context.Context, id pgtype.UUID) (Comment, error) {
@@ -60,20 +171,73 @@ func (q *Queries) GetCommentByID(ctx context.Context, id pgtype.UUID) (Comment,
 		&i.Body,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
+		&i.IsDeleted,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.DeletedWithAccount,
 	)
 	return i, err
 }
 
+const getCommentLike = `-- name: GetCommentLike :one
+SELECT id, comment_id, user_id
+FROM comment_likes
+WHERE comment_id = $1 AND user_id = $2
+`
+
+type GetCommentLikeParams struct {
+	CommentID pgtype.UUID `json:"comment_id"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetCommentLike(ctx context.Context, arg GetCommentLikeParams) (CommentLike, error) {
+	row := q.db.QueryRow(ctx, getCommentLike, arg.CommentID, arg.UserID)
+	var i CommentLike
+	err := row.Scan(&i.ID, &i.CommentID, &i.UserID)
+	return i, err
+}
+
 const getCommentsByPostSlug = `-- name: GetCommentsByPostSlug :many
-SELECT c.id, c.post_id, c.user_id, c.body, c.created_at, c.updated_at
+SELECT c.id, c.post_id, c.user_id, c.body, c.created_at, c.updated_at, c.parent_id, c.is_deleted, c.is_hidden, c.deleted_at, c.deleted_with_account
 FROM comments c
 JOIN posts p ON p.id = c.post_id
 WHERE p.slug = $1
-ORDER BY c.created_at DESC
+AND c.parent_id IS NULL
+AND c.deleted_at IS NULL
+AND p.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = $2 AND m.muted_id = c.user_id
+)
+ORDER BY
+    CASE WHEN $3::text = 'most_liked' THEN (
+        SELECT COUNT(*) FROM comment_likes cl WHERE cl.comment_id = c.id
+    ) END DESC,
+    CASE WHEN $3::text = 'oldest' THEN c.created_at END ASC,
+    c.created_at DESC,
+    c.id
+LIMIT $5 OFFSET $4
 `
 
-func (q *Queries) GetCommentsByPostSlug(ctx context.Context, slug string) ([]Comment, error) {
-	rows, err := q.db.Query(ctx, getCommentsByPostSlug, slug)
+type GetCommentsByPostSlugParams struct {
+	Slug     string      `json:"slug"`
+	ViewerID pgtype.UUID `json:"viewer_id"`
+	Sort     string      `json:"sort"`
+	Offset   int32       `json:"offset"`
+	Limit    int32       `json:"limit"`
+}
+
+// Returns a page of top-level comments; replies are loaded with GetRepliesByRootCommentIDs.
+func (q *Queries) GetCommentsByPostSlug(ctx context.Context, arg GetCommentsByPostSlugParams) ([]Comment, error) {
+	rows, err := q.db.Query(ctx, getCommentsByPostSlug,
+		arg.Slug,
+		arg.ViewerID,
+		arg.Sort,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +252,11 @@ func (q *Queries) GetCommentsByPostSlug(ctx context.Context, slug string) ([]Com
 			&i.Body,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentID,
+			&i.IsDeleted,
+			&i.IsHidden,
+			&i.DeletedAt,
+			&i.DeletedWithAccount,
 		); err != nil {
 			return nil, err
 		}
@@ -99,11 +268,274 @@ func (q *Queries) GetCommentsByPostSlug(ctx context.Context, slug string) ([]Com
 	return items, nil
 }
 
+const getLikeCountsByCommentIDs = `-- name: GetLikeCountsByCommentIDs :many
+SELECT
+    comment_id,
+    COUNT(*)::bigint AS like_count
+FROM comment_likes
+WHERE comment_id = ANY($1::uuid[])
+GROUP BY comment_id
+`
+
+type GetLikeCountsByCommentIDsRow struct {
+	CommentID pgtype.UUID `json:"comment_id"`
+	LikeCount int64       `json:"like_count"`
+}
+
+func (q *Queries) GetLikeCountsByCommentIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]GetLikeCountsByCommentIDsRow, error) {
+	rows, err := q.db.Query(ctx, getLikeCountsByCommentIDs, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLikeCountsByCommentIDsRow
+	for rows.Next() {
+		var i GetLikeCountsByCommentIDsRow
+		if err := rows.Scan(&i.CommentID, &i.LikeCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRepliesByRootCommentIDs = `-- name: GetRepliesByRootCommentIDs :many
+WITH RECURSIVE thread AS (
+    SELECT r.id
+    FROM comments r
+    WHERE r.parent_id = ANY($1::uuid[])
+    AND r.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_mutes m
+        WHERE m.muter_id = $2 AND m.muted_id = r.user_id
+    )
+    UNION ALL
+    SELECT r.id
+    FROM comments r
+    JOIN thread t ON r.parent_id = t.id
+    WHERE r.deleted_at IS NULL
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_mutes m
+        WHERE m.muter_id = $2 AND m.muted_id = r.user_id
+    )
+)
+SELECT c.id, c.post_id, c.user_id, c.body, c.created_at, c.updated_at, c.parent_id, c.is_deleted, c.is_hidden, c.deleted_at, c.deleted_with_account
+FROM comments c
+WHERE c.id IN (SELECT id FROM thread)
+`
+
+type GetRepliesByRootCommentIDsParams struct {
+	RootIds  []pgtype.UUID `json:"root_ids"`
+	ViewerID pgtype.UUID   `json:"viewer_id"`
+}
+
+// Replies by users the viewer muted are left out along with the replies beneath them.
+func (q *Queries) GetRepliesByRootCommentIDs(ctx context.Context, arg GetRepliesByRootCommentIDsParams) ([]Comment, error) {
+	rows, err := q.db.Query(ctx, getRepliesByRootCommentIDs, arg.RootIds, arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Comment
+	for rows.Next() {
+		var i Comment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.UserID,
+			&i.Body,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentID,
+			&i.IsDeleted,
+			&i.IsHidden,
+			&i.DeletedAt,
+			&i.DeletedWithAccount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLikedCommentIDs = `-- name: GetUserLikedCommentIDs :many
+SELECT comment_id
+FROM comment_likes
+WHERE user_id = $1
+AND comment_id = ANY($2::uuid[])
+`
+
+type GetUserLikedCommentIDsParams struct {
+	UserID  pgtype.UUID   `json:"user_id"`
+	Column2 []pgtype.UUID `json:"column_2"`
+}
+
+func (q *Queries) GetUserLikedCommentIDs(ctx context.Context, arg GetUserLikedCommentIDsParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, getUserLikedCommentIDs, arg.UserID, arg.Column2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var comment_id pgtype.UUID
+		if err := rows.Scan(&comment_id); err != nil {
+			return nil, err
+		}
+		items = append(items, comment_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedComments = `-- name: PurgeDeletedComments :execrows
+DELETE FROM comments
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedComments(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedComments, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreCommentsByUserID = `-- name: RestoreCommentsByUserID :exec
+UPDATE comments
+SET deleted_at = NULL, deleted_with_account = FALSE
+WHERE user_id = $1 AND deleted_with_account = TRUE
+`
+
+func (q *Queries) RestoreCommentsByUserID(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, restoreCommentsByUserID, userID)
+	return err
+}
+
+const setCommentHidden = `-- name: SetCommentHidden :one
+UPDATE comments
+SET is_hidden = $2
+WHERE id = $1
+RETURNING id, post_id, user_id, body, created_at, updated_at, parent_id, is_deleted, is_hidden, deleted_at, deleted_with_account
+`
+
+type SetCommentHiddenParams struct {
+	ID       pgtype.UUID `json:"id"`
+	IsHidden bool        `json:"is_hidden"`
+}
+
+func (q *Queries) SetCommentHidden(ctx context.Context, arg SetCommentHiddenParams) (Comment, error) {
+	row := q.db.QueryRow(ctx, setCommentHidden, arg.ID, arg.IsHidden)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.UserID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.IsDeleted,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.DeletedWithAccount,
+	)
+	return i, err
+}
+
+const softDeleteComment = `-- name: SoftDeleteComment :exec
+UPDATE comments
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteComment(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, softDeleteComment, id)
+	return err
+}
+
+const softDeleteCommentsByUserID = `-- name: SoftDeleteCommentsByUserID :exec
+UPDATE comments
+SET deleted_at = now(), deleted_with_account = TRUE
+WHERE user_id = $1 AND is_deleted = FALSE AND deleted_at IS NULL
+`
+
+// Placeholders are skipped, since removing one would also hide the replies below it.
+func (q *Queries) SoftDeleteCommentsByUserID(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, softDeleteCommentsByUserID, userID)
+	return err
+}
+
+const tombstoneComment = `-- name: TombstoneComment :one
+UPDATE comments
+SET body = '', is_deleted = TRUE, updated_at = now()
+WHERE id = $1
+RETURNING id, post_id, user_id, body, created_at, updated_at, parent_id, is_deleted, is_hidden, deleted_at, deleted_with_account
+`
+
+func (q *Queries) TombstoneComment(ctx context.Context, id pgtype.UUID) (Comment, error) {
+	row := q.db.QueryRow(ctx, tombstoneComment, id)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.UserID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.IsDeleted,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.DeletedWithAccount,
+	)
+	return i, err
+}
+
+const tombstoneCommentsWithRepliesByUserID = `-- name: TombstoneCommentsWithRepliesByUserID :exec
+WITH RECURSIVE thread AS (
+    SELECT c.id AS root_id, c.id
+    FROM comments c
+    WHERE c.user_id = $1 AND c.is_deleted = FALSE AND c.deleted_at IS NULL
+    UNION ALL
+    SELECT t.root_id, r.id
+    FROM comments r
+    JOIN thread t ON r.parent_id = t.id
+    WHERE r.deleted_at IS NULL
+)
+UPDATE comments
+SET body = '', is_deleted = TRUE, updated_at = now()
+WHERE id IN (
+    SELECT t.root_id
+    FROM thread t
+    JOIN comments r ON r.id = t.id
+    WHERE r.user_id IS DISTINCT FROM $1 AND r.is_deleted = FALSE
+)
+`
+
+// Replaces the user's comments that have a reply from someone else anywhere below them
+// with placeholders, so deleting the account keeps those threads intact.
+func (q *Queries) TombstoneCommentsWithRepliesByUserID(ctx context.Context, userID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, tombstoneCommentsWithRepliesByUserID, userID)
+	return err
+}
+
 const updateComment = `-- name: UpdateComment :one
 UPDATE comments
 SET body = $2, updated_at = now()
 WHERE id = $1
-RETURNING id, post_id, user_id, body, created_at, updated_at
+RETURNING id, post_id, user_id, body, created_at, updated_at, parent_id, is_deleted, is_hidden, deleted_at, deleted_with_account
 `
 
 type UpdateCommentParams struct {
@@ -121,6 +553,11 @@ func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (C
 		&i.Body,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
+		&i.IsDeleted,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.DeletedWithAccount,
 	)
 	return i, err
 }
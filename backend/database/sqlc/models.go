@@ -18,12 +18,17 @@ type Category struct {
 }
 
 type Comment struct {
-	ID        pgtype.UUID        `json:"id"`
-	PostID    pgtype.UUID        `json:"post_id"`
-	UserID    pgtype.UUID        `json:"user_id"`
-	Body      string             `json:"body"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ID                 pgtype.UUID        `json:"id"`
+	PostID             pgtype.UUID        `json:"post_id"`
+	UserID             pgtype.UUID        `json:"user_id"`
+	Body               string             `json:"body"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ParentID           pgtype.UUID        `json:"parent_id"`
+	IsDeleted          bool               `json:"is_deleted"`
+	IsHidden           bool               `json:"is_hidden"`
+	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	DeletedWithAccount bool               `json:"deleted_with_account"`
 }
 
 type CommentLike struct {
@@ -32,15 +37,46 @@ type CommentLike struct {
 	UserID    pgtype.UUID `json:"user_id"`
 }
 
-type Post struct {
+type ContentFilterVerdict struct {
+	ID         pgtype.UUID        `json:"id"`
+	AuthorID   pgtype.UUID        `json:"author_id"`
+	TargetType string             `json:"target_type"`
+	TargetID   pgtype.UUID        `json:"target_id"`
+	Action     string             `json:"action"`
+	Reasons    []string           `json:"reasons"`
+	Body       string             `json:"body"`
+	Status     string             `json:"status"`
+	ReviewedBy pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type ModerationAction struct {
 	ID          pgtype.UUID        `json:"id"`
-	AuthorID    pgtype.UUID        `json:"author_id"`
-	Title       string             `json:"title"`
-	Slug        string             `json:"slug"`
-	Body        string             `json:"body"`
-	IsPublished bool               `json:"is_published"`
+	ModeratorID pgtype.UUID        `json:"moderator_id"`
+	Action      string             `json:"action"`
+	TargetType  string             `json:"target_type"`
+	TargetID    pgtype.UUID        `json:"target_id"`
+	Reason      string             `json:"reason"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Post struct {
+	ID                 pgtype.UUID        `json:"id"`
+	AuthorID           pgtype.UUID        `json:"author_id"`
+	Title              string             `json:"title"`
+	Slug               string             `json:"slug"`
+	Body               string             `json:"body"`
+	IsPublished        bool               `json:"is_published"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	PublishAt          pgtype.Timestamptz `json:"publish_at"`
+	SearchVector       interface{}        `json:"search_vector"`
+	IsHidden           bool               `json:"is_hidden"`
+	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	PublishedAt        pgtype.Timestamptz `json:"published_at"`
+	DeletedWithAccount bool               `json:"deleted_with_account"`
+	DeletedBy          pgtype.UUID        `json:"deleted_by"`
 }
 
 type PostCategory struct {
@@ -54,18 +90,103 @@ type PostLike struct {
 	UserID pgtype.UUID `json:"user_id"`
 }
 
+type PostRevision struct {
+	ID             pgtype.UUID        `json:"id"`
+	PostID         pgtype.UUID        `json:"post_id"`
+	RevisionNumber int32              `json:"revision_number"`
+	Title          string             `json:"title"`
+	Body           string             `json:"body"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type PostSlugHistory struct {
+	Slug      string             `json:"slug"`
+	PostID    pgtype.UUID        `json:"post_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        pgtype.UUID        `json:"id"`
+	SessionID pgtype.UUID        `json:"session_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Report struct {
+	ID         pgtype.UUID        `json:"id"`
+	ReporterID pgtype.UUID        `json:"reporter_id"`
+	TargetType string             `json:"target_type"`
+	TargetID   pgtype.UUID        `json:"target_id"`
+	Reason     string             `json:"reason"`
+	Details    string             `json:"details"`
+	Status     string             `json:"status"`
+	Resolution pgtype.Text        `json:"resolution"`
+	ResolvedBy pgtype.UUID        `json:"resolved_by"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type Session struct {
+	ID         pgtype.UUID        `json:"id"`
+	UserID     pgtype.UUID        `json:"user_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	UserAgent  string             `json:"user_agent"`
+	IpAddress  string             `json:"ip_address"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+}
+
 type User struct {
-	ID          pgtype.UUID        `json:"id"`
-	GoogleID    string             `json:"google_id"`
-	Username    pgtype.Text        `json:"username"`
-	Email       string             `json:"email"`
-	Description pgtype.Text        `json:"description"`
-	AvatarUrl   pgtype.Text        `json:"avatar_url"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ID              pgtype.UUID        `json:"id"`
+	Username        pgtype.Text        `json:"username"`
+	Email           string             `json:"email"`
+	Description     pgtype.Text        `json:"description"`
+	AvatarUrl       pgtype.Text        `json:"avatar_url"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	DisplayName     pgtype.Text        `json:"display_name"`
+	WebsiteUrl      pgtype.Text        `json:"website_url"`
+	GithubUrl       pgtype.Text        `json:"github_url"`
+	TwitterUrl      pgtype.Text        `json:"twitter_url"`
+	LinkedinUrl     pgtype.Text        `json:"linkedin_url"`
+	Location        pgtype.Text        `json:"location"`
+	CustomAvatarUrl pgtype.Text        `json:"custom_avatar_url"`
+	ShowEmail       bool               `json:"show_email"`
+	Role            string             `json:"role"`
+	SuspendedAt     pgtype.Timestamptz `json:"suspended_at"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type UserBlock struct {
+	BlockerID pgtype.UUID        `json:"blocker_id"`
+	BlockedID pgtype.UUID        `json:"blocked_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type UserFollow struct {
-	FollowerID pgtype.UUID `json:"follower_id"`
-	FolloweeID pgtype.UUID `json:"followee_id"`
+	FollowerID pgtype.UUID        `json:"follower_id"`
+	FolloweeID pgtype.UUID        `json:"followee_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type UserIdentity struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	Provider  string             `json:"provider"`
+	Subject   string             `json:"subject"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type UserMute struct {
+	MuterID   pgtype.UUID        `json:"muter_id"`
+	MutedID   pgtype.UUID        `json:"muted_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type UsernameHistory struct {
+	Username  string             `json:"username"`
+	UserID    pgtype.UUID        `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
@@ -11,18 +11,67 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countPostsByUsername = `-- name: CountPostsByUsername :one
+SELECT COUNT(*)::bigint AS total
+FROM posts p
+JOIN users u ON u.id = p.author_id
+WHERE u.username = $1
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = $2)
+`
+
+type CountPostsByUsernameParams struct {
+	Username pgtype.Text `json:"username"`
+	ViewerID pgtype.UUID `json:"viewer_id"`
+}
+
+func (q *Queries) CountPostsByUsername(ctx context.Context, arg CountPostsByUsernameParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPostsByUsername, arg.Username, arg.ViewerID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const countPostsLikedByUsername = `-- name: CountPostsLikedByUsername :one
+SELECT COUNT(*)::bigint AS total
+FROM post_likes pl
+JOIN users u ON u.id = pl.user_id
+JOIN posts p ON p.id = pl.post_id
+WHERE u.username = $1
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = $2)
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = $2 AND m.muted_id = p.author_id
+)
+`
+
+type CountPostsLikedByUsernameParams struct {
+	Username pgtype.Text `json:"username"`
+	ViewerID pgtype.UUID `json:"viewer_id"`
+}
+
+func (q *Queries) CountPostsLikedByUsername(ctx context.Context, arg CountPostsLikedByUsernameParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPostsLikedByUsername, arg.Username, arg.ViewerID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts (title, body, slug, author_id, is_published)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at
+INSERT INTO posts (title, body, slug, author_id, is_published, publish_at, published_at)
+VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $5::boolean THEN NOW() END)
+RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
 `
 
 type CreatePostParams struct {
-	Title       string      `json:"title"`
-	Body        string      `json:"body"`
-	Slug        string      `json:"slug"`
-	AuthorID    pgtype.UUID `json:"author_id"`
-	IsPublished bool        `json:"is_published"`
+	Title       string             `json:"title"`
+	Body        string             `json:"body"`
+	Slug        string             `json:"slug"`
+	AuthorID    pgtype.UUID        `json:"author_id"`
+	IsPublished bool               `json:"is_published"`
+	PublishAt   pgtype.Timestamptz `json:"publish_at"`
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -32,6 +81,7 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Slug,
 		arg.AuthorID,
 		arg.IsPublished,
+		arg.PublishAt,
 	)
 	var i Post
 	err := row.Scan(
@@ -43,6 +93,13 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.IsPublished,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
 	)
 	return i, err
 }
@@ -66,13 +123,47 @@ func (q *Queries) CreatePostLike(ctx context.Context, arg CreatePostLikeParams)
 	return i, err
 }
 
-const deletePost = `-- name: DeletePost :exec
-DELETE FROM posts
-WHERE id = $1
+const createPostRevision = `-- name: CreatePostRevision :one
+INSERT INTO post_revisions (post_id, revision_number, title, body)
+SELECT $1::uuid, COALESCE(MAX(revision_number), 0) + 1, $2::text, $3::text
+FROM post_revisions
+WHERE post_id = $1::uuid
+RETURNING id, post_id, revision_number, title, body, created_at
+`
+
+type CreatePostRevisionParams struct {
+	PostID pgtype.UUID `json:"post_id"`
+	Title  string      `json:"title"`
+	Body   string      `json:"body"`
+}
+
+func (q *Queries) CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) (PostRevision, error) {
+	row := q.db.QueryRow(ctx, createPostRevision, arg.PostID, arg.Title, arg.Body)
+	var i PostRevision
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.RevisionNumber,
+		&i.Title,
+		&i.Body,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPostSlugHistory = `-- name: CreatePostSlugHistory :exec
+INSERT INTO post_slug_history (slug, post_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO NOTHING
 `
 
-func (q *Queries) DeletePost(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deletePost, id)
+type CreatePostSlugHistoryParams struct {
+	Slug   string      `json:"slug"`
+	PostID pgtype.UUID `json:"post_id"`
+}
+
+func (q *Queries) CreatePostSlugHistory(ctx context.Context, arg CreatePostSlugHistoryParams) error {
+	_, err := q.db.Exec(ctx, createPostSlugHistory, arg.Slug, arg.PostID)
 	return err
 }
 
@@ -91,6 +182,21 @@ func (q *Queries) DeletePostLike(ctx context.Context, arg DeletePostLikeParams)
 	return err
 }
 
+const deletePostSlugHistory = `-- name: DeletePostSlugHistory :exec
+DELETE FROM post_slug_history
+WHERE slug = $1 AND post_id = $2
+`
+
+type DeletePostSlugHistoryParams struct {
+	Slug   string      `json:"slug"`
+	PostID pgtype.UUID `json:"post_id"`
+}
+
+func (q *Queries) DeletePostSlugHistory(ctx context.Context, arg DeletePostSlugHistoryParams) error {
+	_, err := q.db.Exec(ctx, deletePostSlugHistory, arg.Slug, arg.PostID)
+	return err
+}
+
 const getCategoriesByPostIDs = `-- name: GetCategoriesByPostIDs :many
 SELECT
     pc.post_id,
@@ -143,6 +249,9 @@ SELECT
     COUNT(*)::bigint AS comment_count
 FROM comments
 WHERE post_id = ANY($1::uuid[])
+AND is_deleted = FALSE
+AND is_hidden = FALSE
+AND deleted_at IS NULL
 GROUP BY post_id
 `
 
@@ -171,6 +280,163 @@ func (q *Queries) GetCommentCountsByPostIDs(ctx context.Context, dollar_1 []pgty
 	return items, nil
 }
 
+const getCurrentSlugByOldSlug = `-- name: GetCurrentSlugByOldSlug :one
+SELECT p.slug AS current_slug
+FROM post_slug_history h
+JOIN posts p ON p.id = h.post_id
+WHERE h.slug = $1 AND p.deleted_at IS NULL
+`
+
+func (q *Queries) GetCurrentSlugByOldSlug(ctx context.Context, slug string) (string, error) {
+	row := q.db.QueryRow(ctx, getCurrentSlugByOldSlug, slug)
+	var current_slug string
+	err := row.Scan(&current_slug)
+	return current_slug, err
+}
+
+const getDeletedPostByID = `-- name: GetDeletedPostByID :one
+SELECT id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
+FROM posts
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeletedPostByID(ctx context.Context, id pgtype.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, getDeletedPostByID, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Slug,
+		&i.Body,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const getDraftPostsByAuthorID = `-- name: GetDraftPostsByAuthorID :many
+SELECT id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
+FROM posts
+WHERE author_id = $1
+AND is_published = FALSE
+AND deleted_at IS NULL
+ORDER BY updated_at DESC
+`
+
+func (q *Queries) GetDraftPostsByAuthorID(ctx context.Context, authorID pgtype.UUID) ([]Post, error) {
+	rows, err := q.db.Query(ctx, getDraftPostsByAuthorID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.Title,
+			&i.Slug,
+			&i.Body,
+			&i.IsPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.SearchVector,
+			&i.IsHidden,
+			&i.DeletedAt,
+			&i.PublishedAt,
+			&i.DeletedWithAccount,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedPostsByUserID = `-- name: GetFeedPostsByUserID :many
+SELECT p.id, p.author_id, p.title, p.slug, p.body, p.is_published, p.created_at, p.updated_at, p.publish_at, p.search_vector, p.is_hidden, p.deleted_at, p.published_at, p.deleted_with_account, p.deleted_by
+FROM posts p
+JOIN user_follows uf ON uf.followee_id = p.author_id
+WHERE uf.follower_id = $1
+AND p.is_published = TRUE
+AND p.is_hidden = FALSE
+AND p.deleted_at IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = $1 AND m.muted_id = p.author_id
+)
+AND (
+    $2::timestamptz IS NULL
+    OR (p.published_at, p.id) < ($2::timestamptz, $3::uuid)
+)
+ORDER BY p.published_at DESC, p.id DESC
+LIMIT $4
+`
+
+type GetFeedPostsByUserIDParams struct {
+	UserID            pgtype.UUID        `json:"user_id"`
+	CursorPublishedAt pgtype.Timestamptz `json:"cursor_published_at"`
+	CursorID          pgtype.UUID        `json:"cursor_id"`
+	Limit             int32              `json:"limit"`
+}
+
+func (q *Queries) GetFeedPostsByUserID(ctx context.Context, arg GetFeedPostsByUserIDParams) ([]Post, error) {
+	rows, err := q.db.Query(ctx, getFeedPostsByUserID,
+		arg.UserID,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.Title,
+			&i.Slug,
+			&i.Body,
+			&i.IsPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.SearchVector,
+			&i.IsHidden,
+			&i.DeletedAt,
+			&i.PublishedAt,
+			&i.DeletedWithAccount,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLikeCountsByPostIDs = `-- name: GetLikeCountsByPostIDs :many
 SELECT
     post_id,
@@ -206,9 +472,9 @@ func (q *Queries) GetLikeCountsByPostIDs(ctx context.Context, dollar_1 []pgtype.
 }
 
 const getPostByID = `-- name: GetPostByID :one
-SELECT id, author_id, title, slug, body, is_published, created_at, updated_at
+SELECT id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
 FROM posts
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPostByID(ctx context.Context, id pgtype.UUID) (Post, error) {
@@ -223,32 +489,74 @@ func (q *Queries) GetPostByID(ctx context.Context, id pgtype.UUID) (Post, error)
 		&i.IsPublished,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
 	)
 	return i, err
 }
 
 const getPostBySearchAndCategoryPaginated = `-- name: GetPostBySearchAndCategoryPaginated :many
-SELECT DISTINCT p.id, p.author_id, p.title, p.slug, p.body, p.is_published, p.created_at, p.updated_at
+SELECT
+    p.id, p.author_id, p.title, p.slug, p.body, p.is_published, p.created_at, p.updated_at, p.publish_at, p.search_vector, p.is_hidden, p.deleted_at, p.published_at, p.deleted_with_account, p.deleted_by,
+    COALESCE(ts_rank(p.search_vector, websearch_to_tsquery('english', $1::text)), 0)::real AS rank,
+    COALESCE(ts_headline('english', p.body, websearch_to_tsquery('english', $1::text), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10'), '')::text AS snippet
 FROM posts p
-LEFT JOIN post_categories pc ON pc.post_id = p.id
-LEFT JOIN categories c ON c.id = pc.category_id
-WHERE ($1::text IS NULL OR $1 = '' OR c.slug = $1)
-AND ($2::text IS NULL OR $2 = '' OR p.title ILIKE '%' || $2 || '%')
-ORDER BY p.created_at DESC
-LIMIT $4 OFFSET $3
+WHERE p.is_published = TRUE
+AND p.is_hidden = FALSE
+AND p.deleted_at IS NULL
+AND ($2::text IS NULL OR EXISTS (
+    SELECT 1
+    FROM post_categories pc
+    JOIN categories c ON c.id = pc.category_id
+    WHERE pc.post_id = p.id AND c.slug = $2
+))
+AND ($1::text IS NULL OR p.search_vector @@ websearch_to_tsquery('english', $1::text))
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = $3 AND m.muted_id = p.author_id
+)
+AND (
+    $4::timestamptz IS NULL
+    OR (p.published_at, p.id) < ($4::timestamptz, $5::uuid)
+)
+ORDER BY
+    CASE WHEN $6::text = 'relevance' THEN ts_rank(p.search_vector, websearch_to_tsquery('english', $1::text)) END DESC,
+    p.published_at DESC,
+    p.id DESC
+LIMIT $8 OFFSET $7
 `
 
 type GetPostBySearchAndCategoryPaginatedParams struct {
-	CategorySlug pgtype.Text `json:"category_slug"`
-	Search       pgtype.Text `json:"search"`
-	Offset       int32       `json:"offset"`
-	Limit        int32       `json:"limit"`
+	Search            pgtype.Text        `json:"search"`
+	CategorySlug      pgtype.Text        `json:"category_slug"`
+	ViewerID          pgtype.UUID        `json:"viewer_id"`
+	CursorPublishedAt pgtype.Timestamptz `json:"cursor_published_at"`
+	CursorID          pgtype.UUID        `json:"cursor_id"`
+	Sort              string             `json:"sort"`
+	Offset            int32              `json:"offset"`
+	Limit             int32              `json:"limit"`
 }
 
-func (q *Queries) GetPostBySearchAndCategoryPaginated(ctx context.Context, arg GetPostBySearchAndCategoryPaginatedParams) ([]Post, error) {
+type GetPostBySearchAndCategoryPaginatedRow struct {
+	Post    Post    `json:"post"`
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+func (q *Queries) GetPostBySearchAndCategoryPaginated(ctx context.Context, arg GetPostBySearchAndCategoryPaginatedParams) ([]GetPostBySearchAndCategoryPaginatedRow, error) {
 	rows, err := q.db.Query(ctx, getPostBySearchAndCategoryPaginated,
-		arg.CategorySlug,
 		arg.Search,
+		arg.CategorySlug,
+		arg.ViewerID,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.Sort,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -256,18 +564,27 @@ func (q *Queries) GetPostBySearchAndCategoryPaginated(ctx context.Context, arg G
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Post
+	var items []GetPostBySearchAndCategoryPaginatedRow
 	for rows.Next() {
-		var i Post
+		var i GetPostBySearchAndCategoryPaginatedRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.AuthorID,
-			&i.Title,
-			&i.Slug,
-			&i.Body,
-			&i.IsPublished,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Post.ID,
+			&i.Post.AuthorID,
+			&i.Post.Title,
+			&i.Post.Slug,
+			&i.Post.Body,
+			&i.Post.IsPublished,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.PublishAt,
+			&i.Post.SearchVector,
+			&i.Post.IsHidden,
+			&i.Post.DeletedAt,
+			&i.Post.PublishedAt,
+			&i.Post.DeletedWithAccount,
+			&i.Post.DeletedBy,
+			&i.Rank,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -280,9 +597,9 @@ func (q *Queries) GetPostBySearchAndCategoryPaginated(ctx context.Context, arg G
 }
 
 const getPostBySlug = `-- name: GetPostBySlug :one
-SELECT id, author_id, title, slug, body, is_published, created_at, updated_at 
+SELECT id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by 
 FROM posts 
-WHERE slug = $1
+WHERE slug = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error) {
@@ -297,6 +614,13 @@ func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error)
 		&i.IsPublished,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
 	)
 	return i, err
 }
@@ -319,16 +643,98 @@ func (q *Queries) GetPostLike(ctx context.Context, arg GetPostLikeParams) (PostL
 	return i, err
 }
 
+const getPostRevision = `-- name: GetPostRevision :one
+SELECT id, post_id, revision_number, title, body, created_at
+FROM post_revisions
+WHERE post_id = $1 AND revision_number = $2
+`
+
+type GetPostRevisionParams struct {
+	PostID         pgtype.UUID `json:"post_id"`
+	RevisionNumber int32       `json:"revision_number"`
+}
+
+func (q *Queries) GetPostRevision(ctx context.Context, arg GetPostRevisionParams) (PostRevision, error) {
+	row := q.db.QueryRow(ctx, getPostRevision, arg.PostID, arg.RevisionNumber)
+	var i PostRevision
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.RevisionNumber,
+		&i.Title,
+		&i.Body,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPostRevisionsByPostID = `-- name: GetPostRevisionsByPostID :many
+SELECT id, post_id, revision_number, title, body, created_at
+FROM post_revisions
+WHERE post_id = $1
+ORDER BY revision_number DESC
+`
+
+func (q *Queries) GetPostRevisionsByPostID(ctx context.Context, postID pgtype.UUID) ([]PostRevision, error) {
+	rows, err := q.db.Query(ctx, getPostRevisionsByPostID, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PostRevision
+	for rows.Next() {
+		var i PostRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.RevisionNumber,
+			&i.Title,
+			&i.Body,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPostsByUsername = `-- name: GetPostsByUsername :many
-SELECT p.id, p.author_id, p.title, p.slug, p.body, p.is_published, p.created_at, p.updated_at
+SELECT p.id, p.author_id, p.title, p.slug, p.body, p.is_published, p.created_at, p.updated_at, p.publish_at, p.search_vector, p.is_hidden, p.deleted_at, p.published_at, p.deleted_with_account, p.deleted_by
 FROM posts p
 JOIN users u ON u.id = p.author_id
 WHERE u.username = $1
-ORDER BY p.created_at DESC
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = $2)
+AND (
+    $3::timestamptz IS NULL
+    OR (COALESCE(p.published_at, p.created_at), p.id) < ($3::timestamptz, $4::uuid)
+)
+ORDER BY COALESCE(p.published_at, p.created_at) DESC, p.id DESC
+LIMIT $6 OFFSET $5
 `
 
-func (q *Queries) GetPostsByUsername(ctx context.Context, username pgtype.Text) ([]Post, error) {
-	rows, err := q.db.Query(ctx, getPostsByUsername, username)
+type GetPostsByUsernameParams struct {
+	Username          pgtype.Text        `json:"username"`
+	ViewerID          pgtype.UUID        `json:"viewer_id"`
+	CursorPublishedAt pgtype.Timestamptz `json:"cursor_published_at"`
+	CursorID          pgtype.UUID        `json:"cursor_id"`
+	Offset            int32              `json:"offset"`
+	Limit             int32              `json:"limit"`
+}
+
+func (q *Queries) GetPostsByUsername(ctx context.Context, arg GetPostsByUsernameParams) ([]Post, error) {
+	rows, err := q.db.Query(ctx, getPostsByUsername,
+		arg.Username,
+		arg.ViewerID,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -345,6 +751,13 @@ func (q *Queries) GetPostsByUsername(ctx context.Context, username pgtype.Text)
 			&i.IsPublished,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.SearchVector,
+			&i.IsHidden,
+			&i.DeletedAt,
+			&i.PublishedAt,
+			&i.DeletedWithAccount,
+			&i.DeletedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -357,16 +770,44 @@ func (q *Queries) GetPostsByUsername(ctx context.Context, username pgtype.Text)
 }
 
 const getPostsLikedByUsername = `-- name: GetPostsLikedByUsername :many
-SELECT p.id, p.author_id, p.title, p.slug, p.body, p.is_published, p.created_at, p.updated_at
+SELECT p.id, p.author_id, p.title, p.slug, p.body, p.is_published, p.created_at, p.updated_at, p.publish_at, p.search_vector, p.is_hidden, p.deleted_at, p.published_at, p.deleted_with_account, p.deleted_by
 FROM post_likes pl
 JOIN users u ON u.id = pl.user_id
 JOIN posts p ON p.id = pl.post_id
 WHERE u.username = $1
-ORDER BY p.created_at DESC
+AND p.deleted_at IS NULL
+AND ((p.is_published = TRUE AND p.is_hidden = FALSE) OR p.author_id = $2)
+AND NOT EXISTS (
+    SELECT 1
+    FROM user_mutes m
+    WHERE m.muter_id = $2 AND m.muted_id = p.author_id
+)
+AND (
+    $3::timestamptz IS NULL
+    OR (COALESCE(p.published_at, p.created_at), p.id) < ($3::timestamptz, $4::uuid)
+)
+ORDER BY COALESCE(p.published_at, p.created_at) DESC, p.id DESC
+LIMIT $6 OFFSET $5
 `
 
-func (q *Queries) GetPostsLikedByUsername(ctx context.Context, username pgtype.Text) ([]Post, error) {
-	rows, err := q.db.Query(ctx, getPostsLikedByUsername, username)
+type GetPostsLikedByUsernameParams struct {
+	Username          pgtype.Text        `json:"username"`
+	ViewerID          pgtype.UUID        `json:"viewer_id"`
+	CursorPublishedAt pgtype.Timestamptz `json:"cursor_published_at"`
+	CursorID          pgtype.UUID        `json:"cursor_id"`
+	Offset            int32              `json:"offset"`
+	Limit             int32              `json:"limit"`
+}
+
+func (q *Queries) GetPostsLikedByUsername(ctx context.Context, arg GetPostsLikedByUsernameParams) ([]Post, error) {
+	rows, err := q.db.Query(ctx, getPostsLikedByUsername,
+		arg.Username,
+		arg.ViewerID,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -383,6 +824,13 @@ func (q *Queries) GetPostsLikedByUsername(ctx context.Context, username pgtype.T
 			&i.IsPublished,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.SearchVector,
+			&i.IsHidden,
+			&i.DeletedAt,
+			&i.PublishedAt,
+			&i.DeletedWithAccount,
+			&i.DeletedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -426,21 +874,256 @@ func (q *Queries) GetUserLikedPostIDs(ctx context.Context, arg GetUserLikedPostI
 	return items, nil
 }
 
-const updatePost = `-- name: UpdatePost :one
+const isSlugTaken = `-- name: IsSlugTaken :one
+SELECT (
+    EXISTS (SELECT 1 FROM posts WHERE slug = $1 AND id IS DISTINCT FROM $2)
+    OR EXISTS (SELECT 1 FROM post_slug_history WHERE slug = $1 AND post_id IS DISTINCT FROM $2)
+)::boolean AS is_taken
+`
+
+type IsSlugTakenParams struct {
+	Slug   string      `json:"slug"`
+	PostID pgtype.UUID `json:"post_id"`
+}
+
+func (q *Queries) IsSlugTaken(ctx context.Context, arg IsSlugTakenParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isSlugTaken, arg.Slug, arg.PostID)
+	var is_taken bool
+	err := row.Scan(&is_taken)
+	return is_taken, err
+}
+
+const publishDuePosts = `-- name: PublishDuePosts :many
+UPDATE posts
+SET is_published = TRUE, publish_at = NULL, published_at = COALESCE(published_at, NOW()), updated_at = NOW()
+WHERE id IN (
+    SELECT id
+    FROM posts
+    WHERE is_published = FALSE
+    AND publish_at IS NOT NULL
+    AND publish_at <= NOW()
+    AND deleted_at IS NULL
+    ORDER BY publish_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
+`
+
+func (q *Queries) PublishDuePosts(ctx context.Context, batchSize int32) ([]Post, error) {
+	rows, err := q.db.Query(ctx, publishDuePosts, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.AuthorID,
+			&i.Title,
+			&i.Slug,
+			&i.Body,
+			&i.IsPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.SearchVector,
+			&i.IsHidden,
+			&i.DeletedAt,
+			&i.PublishedAt,
+			&i.DeletedWithAccount,
+			&i.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedPosts = `-- name: PurgeDeletedPosts :execrows
+DELETE FROM posts
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedPosts(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedPosts, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restorePost = `-- name: RestorePost :one
+UPDATE posts
+SET deleted_at = NULL, deleted_by = NULL
+WHERE id = $1 AND author_id = $2 AND deleted_by = author_id AND deleted_at >= $3
+RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
+`
+
+type RestorePostParams struct {
+	ID           pgtype.UUID        `json:"id"`
+	AuthorID     pgtype.UUID        `json:"author_id"`
+	DeletedAfter pgtype.Timestamptz `json:"deleted_after"`
+}
+
+// Only posts the author deleted themselves can be restored.
+func (q *Queries) RestorePost(ctx context.Context, arg RestorePostParams) (Post, error) {
+	row := q.db.QueryRow(ctx, restorePost, arg.ID, arg.AuthorID, arg.DeletedAfter)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Slug,
+		&i.Body,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const restorePostsByAuthorID = `-- name: RestorePostsByAuthorID :exec
+UPDATE posts
+SET deleted_at = NULL, deleted_by = NULL, deleted_with_account = FALSE
+WHERE author_id = $1 AND deleted_with_account = TRUE
+`
+
+func (q *Queries) RestorePostsByAuthorID(ctx context.Context, authorID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, restorePostsByAuthorID, authorID)
+	return err
+}
+
+const setPostPublished = `-- name: SetPostPublished :one
 UPDATE posts
-SET title = $2, body = $3, slug = $4, is_published = $5, updated_at = NOW()
+SET is_published = $2, publish_at = NULL,
+    published_at = CASE WHEN $2::boolean THEN COALESCE(published_at, NOW()) ELSE published_at END,
+    updated_at = NOW()
 WHERE id = $1
-RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at
+RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
 `
 
-type UpdatePostParams struct {
+type SetPostPublishedParams struct {
 	ID          pgtype.UUID `json:"id"`
-	Title       string      `json:"title"`
-	Body        string      `json:"body"`
-	Slug        string      `json:"slug"`
 	IsPublished bool        `json:"is_published"`
 }
 
+func (q *Queries) SetPostPublished(ctx context.Context, arg SetPostPublishedParams) (Post, error) {
+	row := q.db.QueryRow(ctx, setPostPublished, arg.ID, arg.IsPublished)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Slug,
+		&i.Body,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const setPostHidden = `-- name: SetPostHidden :one
+UPDATE posts
+SET is_hidden = $2
+WHERE id = $1
+RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
+`
+
+type SetPostHiddenParams struct {
+	ID       pgtype.UUID `json:"id"`
+	IsHidden bool        `json:"is_hidden"`
+}
+
+func (q *Queries) SetPostHidden(ctx context.Context, arg SetPostHiddenParams) (Post, error) {
+	row := q.db.QueryRow(ctx, setPostHidden, arg.ID, arg.IsHidden)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Slug,
+		&i.Body,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const softDeletePost = `-- name: SoftDeletePost :exec
+UPDATE posts
+SET deleted_at = now(), deleted_by = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SoftDeletePostParams struct {
+	ID        pgtype.UUID `json:"id"`
+	DeletedBy pgtype.UUID `json:"deleted_by"`
+}
+
+func (q *Queries) SoftDeletePost(ctx context.Context, arg SoftDeletePostParams) error {
+	_, err := q.db.Exec(ctx, softDeletePost, arg.ID, arg.DeletedBy)
+	return err
+}
+
+const softDeletePostsByAuthorID = `-- name: SoftDeletePostsByAuthorID :exec
+UPDATE posts
+SET deleted_at = now(), deleted_by = author_id, deleted_with_account = TRUE
+WHERE author_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeletePostsByAuthorID(ctx context.Context, authorID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, softDeletePostsByAuthorID, authorID)
+	return err
+}
+
+const updatePost = `-- name: UpdatePost :one
+UPDATE posts
+SET title = $2, body = $3, slug = $4, is_published = $5, publish_at = $6,
+    published_at = CASE WHEN $5::boolean THEN COALESCE(published_at, NOW()) ELSE published_at END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
+`
+
+type UpdatePostParams struct {
+	ID          pgtype.UUID        `json:"id"`
+	Title       string             `json:"title"`
+	Body        string             `json:"body"`
+	Slug        string             `json:"slug"`
+	IsPublished bool               `json:"is_published"`
+	PublishAt   pgtype.Timestamptz `json:"publish_at"`
+}
+
 func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error) {
 	row := q.db.QueryRow(ctx, updatePost,
 		arg.ID,
@@ -448,7 +1131,44 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		arg.Body,
 		arg.Slug,
 		arg.IsPublished,
+		arg.PublishAt,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Slug,
+		&i.Body,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
 	)
+	return i, err
+}
+
+const updatePostContent = `-- name: UpdatePostContent :one
+UPDATE posts
+SET title = $2, body = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, author_id, title, slug, body, is_published, created_at, updated_at, publish_at, search_vector, is_hidden, deleted_at, published_at, deleted_with_account, deleted_by
+`
+
+type UpdatePostContentParams struct {
+	ID    pgtype.UUID `json:"id"`
+	Title string      `json:"title"`
+	Body  string      `json:"body"`
+}
+
+func (q *Queries) UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) (Post, error) {
+	row := q.db.QueryRow(ctx, updatePostContent, arg.ID, arg.Title, arg.Body)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -459,6 +1179,13 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		&i.IsPublished,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+		&i.IsHidden,
+		&i.DeletedAt,
+		&i.PublishedAt,
+		&i.DeletedWithAccount,
+		&i.DeletedBy,
 	)
 	return i, err
 }
@@ -12,35 +12,128 @@ import (
 
 type Querier interface {
 	BatchCreatePostCategories(ctx context.Context, arg BatchCreatePostCategoriesParams) error
+	BlockUser(ctx context.Context, arg BlockUserParams) error
+	CountCommentsByPostSlug(ctx context.Context, arg CountCommentsByPostSlugParams) (int64, error)
+	CountFollowersByUserID(ctx context.Context, userID pgtype.UUID) (int64, error)
+	CountFollowingByUserID(ctx context.Context, userID pgtype.UUID) (int64, error)
+	CountPostsByUsername(ctx context.Context, arg CountPostsByUsernameParams) (int64, error)
+	CountPostsLikedByUsername(ctx context.Context, arg CountPostsLikedByUsernameParams) (int64, error)
+	// Bodies are compared lowercased with whitespace collapsed; body must be normalized the same way.
+	CountRecentDuplicateComments(ctx context.Context, arg CountRecentDuplicateCommentsParams) (CountRecentDuplicateCommentsRow, error)
+	CountRepliesByCommentID(ctx context.Context, parentID pgtype.UUID) (int64, error)
+	CountVisibleContentByUserID(ctx context.Context, authorID pgtype.UUID) (int64, error)
 	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
+	CreateCommentLike(ctx context.Context, arg CreateCommentLikeParams) (CommentLike, error)
+	CreateContentFilterVerdict(ctx context.Context, arg CreateContentFilterVerdictParams) (ContentFilterVerdict, error)
+	CreateModerationAction(ctx context.Context, arg CreateModerationActionParams) error
 	CreatePost(ctx context.Context, arg CreatePostParams) (Post, error)
 	CreatePostLike(ctx context.Context, arg CreatePostLikeParams) (PostLike, error)
+	CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) (PostRevision, error)
+	CreatePostSlugHistory(ctx context.Context, arg CreatePostSlugHistoryParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateReport(ctx context.Context, arg CreateReportParams) (Report, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteComment(ctx context.Context, id pgtype.UUID) error
-	DeletePost(ctx context.Context, id pgtype.UUID) error
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	CreateUsernameHistory(ctx context.Context, arg CreateUsernameHistoryParams) error
+	DeleteCommentLike(ctx context.Context, arg DeleteCommentLikeParams) error
 	DeletePostCategoriesByPostID(ctx context.Context, postID pgtype.UUID) error
 	DeletePostLike(ctx context.Context, arg DeletePostLikeParams) error
-	DeleteUserByID(ctx context.Context, id pgtype.UUID) error
+	DeletePostSlugHistory(ctx context.Context, arg DeletePostSlugHistoryParams) error
+	DeleteUsernameHistory(ctx context.Context, arg DeleteUsernameHistoryParams) error
+	FollowUser(ctx context.Context, arg FollowUserParams) error
+	// A session is active until revoked or until its latest refresh token expires.
+	GetActiveSessionsByUserID(ctx context.Context, userID pgtype.UUID) ([]Session, error)
 	GetCategories(ctx context.Context) ([]Category, error)
 	GetCategoriesByPostIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]GetCategoriesByPostIDsRow, error)
 	GetCommentByID(ctx context.Context, id pgtype.UUID) (Comment, error)
 	GetCommentCountsByPostIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]GetCommentCountsByPostIDsRow, error)
-	GetCommentsByPostSlug(ctx context.Context, slug string) ([]Comment, error)
+	GetCommentLike(ctx context.Context, arg GetCommentLikeParams) (CommentLike, error)
+	// Returns a page of top-level comments; replies are loaded with GetRepliesByRootCommentIDs.
+	GetCommentsByPostSlug(ctx context.Context, arg GetCommentsByPostSlugParams) ([]Comment, error)
+	GetContentFilterVerdictByID(ctx context.Context, id pgtype.UUID) (ContentFilterVerdict, error)
+	GetContentFilterVerdicts(ctx context.Context, arg GetContentFilterVerdictsParams) ([]ContentFilterVerdict, error)
+	GetCurrentSlugByOldSlug(ctx context.Context, slug string) (string, error)
+	GetCurrentUsernameByOldUsername(ctx context.Context, username string) (pgtype.Text, error)
+	GetDeletedPostByID(ctx context.Context, id pgtype.UUID) (Post, error)
+	GetDraftPostsByAuthorID(ctx context.Context, authorID pgtype.UUID) ([]Post, error)
+	GetFeedPostsByUserID(ctx context.Context, arg GetFeedPostsByUserIDParams) ([]Post, error)
+	GetFollowCountsByUserID(ctx context.Context, userID pgtype.UUID) (GetFollowCountsByUserIDRow, error)
+	GetFollowersByUserID(ctx context.Context, arg GetFollowersByUserIDParams) ([]User, error)
+	GetFollowingByUserID(ctx context.Context, arg GetFollowingByUserIDParams) ([]User, error)
+	GetLikeCountsByCommentIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]GetLikeCountsByCommentIDsRow, error)
 	GetLikeCountsByPostIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]GetLikeCountsByPostIDsRow, error)
+	GetModerationActions(ctx context.Context, arg GetModerationActionsParams) ([]ModerationAction, error)
 	GetPostByID(ctx context.Context, id pgtype.UUID) (Post, error)
-	GetPostBySearchAndCategoryPaginated(ctx context.Context, arg GetPostBySearchAndCategoryPaginatedParams) ([]Post, error)
+	GetPostBySearchAndCategoryPaginated(ctx context.Context, arg GetPostBySearchAndCategoryPaginatedParams) ([]GetPostBySearchAndCategoryPaginatedRow, error)
 	GetPostBySlug(ctx context.Context, slug string) (Post, error)
 	GetPostLike(ctx context.Context, arg GetPostLikeParams) (PostLike, error)
-	GetPostsByUsername(ctx context.Context, username pgtype.Text) ([]Post, error)
-	GetPostsLikedByUsername(ctx context.Context, username pgtype.Text) ([]Post, error)
-	GetUserByGoogleID(ctx context.Context, googleID string) (User, error)
+	GetPostRevision(ctx context.Context, arg GetPostRevisionParams) (PostRevision, error)
+	GetPostRevisionsByPostID(ctx context.Context, postID pgtype.UUID) ([]PostRevision, error)
+	GetPostsByUsername(ctx context.Context, arg GetPostsByUsernameParams) ([]Post, error)
+	GetPostsLikedByUsername(ctx context.Context, arg GetPostsLikedByUsernameParams) ([]Post, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	// Replies by users the viewer muted are left out along with the replies beneath them.
+	GetRepliesByRootCommentIDs(ctx context.Context, arg GetRepliesByRootCommentIDsParams) ([]Comment, error)
+	GetReportByID(ctx context.Context, id pgtype.UUID) (Report, error)
+	// Locks the report so only one moderator can resolve it.
+	GetReportByIDForUpdate(ctx context.Context, id pgtype.UUID) (Report, error)
+	GetReports(ctx context.Context, arg GetReportsParams) ([]Report, error)
+	GetSessionByID(ctx context.Context, id pgtype.UUID) (Session, error)
+	GetUserByEmail(ctx context.Context, lower string) (User, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
+	GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error)
 	GetUserByUsername(ctx context.Context, username pgtype.Text) (User, error)
+	GetUserLikedCommentIDs(ctx context.Context, arg GetUserLikedCommentIDsParams) ([]pgtype.UUID, error)
 	GetUserLikedPostIDs(ctx context.Context, arg GetUserLikedPostIDsParams) ([]pgtype.UUID, error)
 	GetUsersByIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]User, error)
+	HasAdmin(ctx context.Context) (bool, error)
+	IsBlockedByUser(ctx context.Context, arg IsBlockedByUserParams) (bool, error)
+	IsFollowingUser(ctx context.Context, arg IsFollowingUserParams) (bool, error)
+	IsSlugTaken(ctx context.Context, arg IsSlugTakenParams) (bool, error)
+	IsUsernameTaken(ctx context.Context, arg IsUsernameTakenParams) (bool, error)
+	MarkRefreshTokenUsed(ctx context.Context, id pgtype.UUID) error
+	MuteUser(ctx context.Context, arg MuteUserParams) error
+	PublishDuePosts(ctx context.Context, batchSize int32) ([]Post, error)
+	PurgeDeletedComments(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error)
+	PurgeDeletedPosts(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error)
+	PurgeDeletedUsers(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error)
+	ResolveReportsByTarget(ctx context.Context, arg ResolveReportsByTargetParams) error
+	RestoreCommentsByUserID(ctx context.Context, userID pgtype.UUID) error
+	// Only posts the author deleted themselves can be restored.
+	RestorePost(ctx context.Context, arg RestorePostParams) (Post, error)
+	RestorePostsByAuthorID(ctx context.Context, authorID pgtype.UUID) error
+	RestoreUser(ctx context.Context, id pgtype.UUID) (User, error)
+	ReviewContentFilterVerdict(ctx context.Context, arg ReviewContentFilterVerdictParams) (ContentFilterVerdict, error)
+	RevokeSession(ctx context.Context, id pgtype.UUID) error
+	RevokeSessionsByUserID(ctx context.Context, userID pgtype.UUID) error
+	RevokeUserSession(ctx context.Context, arg RevokeUserSessionParams) (int64, error)
+	SetCommentHidden(ctx context.Context, arg SetCommentHiddenParams) (Comment, error)
+	SetPostHidden(ctx context.Context, arg SetPostHiddenParams) (Post, error)
+	SetPostPublished(ctx context.Context, arg SetPostPublishedParams) (Post, error)
+	SoftDeleteComment(ctx context.Context, id pgtype.UUID) error
+	// Placeholders are skipped, since removing one would also hide the replies below it.
+	SoftDeleteCommentsByUserID(ctx context.Context, userID pgtype.UUID) error
+	SoftDeletePost(ctx context.Context, arg SoftDeletePostParams) error
+	SoftDeletePostsByAuthorID(ctx context.Context, authorID pgtype.UUID) error
+	SoftDeleteUser(ctx context.Context, id pgtype.UUID) error
+	SuspendUser(ctx context.Context, id pgtype.UUID) (User, error)
+	TombstoneComment(ctx context.Context, id pgtype.UUID) (Comment, error)
+	// Replaces the user's comments that have a reply from someone else anywhere below them
+	// with placeholders, so deleting the account keeps those threads intact.
+	TombstoneCommentsWithRepliesByUserID(ctx context.Context, userID pgtype.UUID) error
+	// Throttled so authenticated requests don't write on every call.
+	TouchSession(ctx context.Context, id pgtype.UUID) error
+	UnblockUser(ctx context.Context, arg UnblockUserParams) error
+	UnfollowUser(ctx context.Context, arg UnfollowUserParams) error
+	UnmuteUser(ctx context.Context, arg UnmuteUserParams) error
+	UnsuspendUser(ctx context.Context, id pgtype.UUID) (User, error)
 	UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error)
 	UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error)
+	UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) (Post, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateUsername(ctx context.Context, arg UpdateUsernameParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
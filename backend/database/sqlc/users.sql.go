@@ -11,73 +11,332 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const blockUser = `-- name: BlockUser :exec
+INSERT INTO user_blocks (blocker_id, blocked_id)
+VALUES ($1, $2)
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+`
+
+type BlockUserParams struct {
+	BlockerID pgtype.UUID `json:"blocker_id"`
+	BlockedID pgtype.UUID `json:"blocked_id"`
+}
+
+func (q *Queries) BlockUser(ctx context.Context, arg BlockUserParams) error {
+	_, err := q.db.Exec(ctx, blockUser, arg.BlockerID, arg.BlockedID)
+	return err
+}
+
+const countFollowersByUserID = `-- name: CountFollowersByUserID :one
+SELECT COUNT(*)::bigint AS total
+FROM user_follows uf
+JOIN users u ON u.id = uf.follower_id
+WHERE uf.followee_id = $1
+AND u.deleted_at IS NULL
+`
+
+func (q *Queries) CountFollowersByUserID(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countFollowersByUserID, userID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const countFollowingByUserID = `-- name: CountFollowingByUserID :one
+SELECT COUNT(*)::bigint AS total
+FROM user_follows uf
+JOIN users u ON u.id = uf.followee_id
+WHERE uf.follower_id = $1
+AND u.deleted_at IS NULL
+`
+
+func (q *Queries) CountFollowingByUserID(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countFollowingByUserID, userID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const countVisibleContentByUserID = `-- name: CountVisibleContentByUserID :one
+SELECT (
+    (SELECT COUNT(*) FROM posts
+     WHERE author_id = $1 AND is_published AND NOT is_hidden AND deleted_at IS NULL)
+    + (SELECT COUNT(*) FROM comments
+       WHERE user_id = $1 AND NOT is_deleted AND NOT is_hidden AND deleted_at IS NULL)
+)::bigint AS content_count
+`
+
+func (q *Queries) CountVisibleContentByUserID(ctx context.Context, authorID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countVisibleContentByUserID, authorID)
+	var content_count int64
+	err := row.Scan(&content_count)
+	return content_count, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (google_id, username, email, avatar_url)
-VALUES ($1, $2, $3, $4)
-RETURNING id, google_id, username, email, description, avatar_url, created_at, updated_at
+INSERT INTO users (username, email, avatar_url)
+VALUES ($1, $2, $3)
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
 `
 
 type CreateUserParams struct {
-	GoogleID  string      `json:"google_id"`
 	Username  pgtype.Text `json:"username"`
 	Email     string      `json:"email"`
 	AvatarUrl pgtype.Text `json:"avatar_url"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser,
-		arg.GoogleID,
-		arg.Username,
-		arg.Email,
-		arg.AvatarUrl,
-	)
+	row := q.db.QueryRow(ctx, createUser, arg.Username, arg.Email, arg.AvatarUrl)
 	var i User
 	err := row.Scan(
 		&i.ID,
-		&i.GoogleID,
 		&i.Username,
 		&i.Email,
 		&i.Description,
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const deleteUserByID = `-- name: DeleteUserByID :exec
-DELETE FROM users WHERE id = $1
+const createUsernameHistory = `-- name: CreateUsernameHistory :exec
+INSERT INTO username_history (username, user_id)
+VALUES ($1, $2)
+ON CONFLICT (username) DO NOTHING
+`
+
+type CreateUsernameHistoryParams struct {
+	Username string      `json:"username"`
+	UserID   pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) CreateUsernameHistory(ctx context.Context, arg CreateUsernameHistoryParams) error {
+	_, err := q.db.Exec(ctx, createUsernameHistory, arg.Username, arg.UserID)
+	return err
+}
+
+const deleteUsernameHistory = `-- name: DeleteUsernameHistory :exec
+DELETE FROM username_history
+WHERE username = $1 AND user_id = $2
 `
 
-func (q *Queries) DeleteUserByID(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteUserByID, id)
+type DeleteUsernameHistoryParams struct {
+	Username string      `json:"username"`
+	UserID   pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteUsernameHistory(ctx context.Context, arg DeleteUsernameHistoryParams) error {
+	_, err := q.db.Exec(ctx, deleteUsernameHistory, arg.Username, arg.UserID)
 	return err
 }
 
-const getUserByGoogleID = `-- name: GetUserByGoogleID :one
-SELECT id, google_id, username, email, description, avatar_url, created_at, updated_at FROM users 
-WHERE google_id = $1
+const followUser = `-- name: FollowUser :exec
+INSERT INTO user_follows (follower_id, followee_id)
+VALUES ($1, $2)
+ON CONFLICT (follower_id, followee_id) DO NOTHING
+`
+
+type FollowUserParams struct {
+	FollowerID pgtype.UUID `json:"follower_id"`
+	FolloweeID pgtype.UUID `json:"followee_id"`
+}
+
+func (q *Queries) FollowUser(ctx context.Context, arg FollowUserParams) error {
+	_, err := q.db.Exec(ctx, followUser, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const getCurrentUsernameByOldUsername = `-- name: GetCurrentUsernameByOldUsername :one
+SELECT u.username AS current_username
+FROM username_history h
+JOIN users u ON u.id = h.user_id
+WHERE h.username = $1 AND u.deleted_at IS NULL
+`
+
+func (q *Queries) GetCurrentUsernameByOldUsername(ctx context.Context, username string) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getCurrentUsernameByOldUsername, username)
+	var current_username pgtype.Text
+	err := row.Scan(&current_username)
+	return current_username, err
+}
+
+const getFollowCountsByUserID = `-- name: GetFollowCountsByUserID :one
+SELECT
+    (SELECT COUNT(*) FROM user_follows uf JOIN users u ON u.id = uf.follower_id
+     WHERE uf.followee_id = $1 AND u.deleted_at IS NULL)::bigint AS follower_count,
+    (SELECT COUNT(*) FROM user_follows uf JOIN users u ON u.id = uf.followee_id
+     WHERE uf.follower_id = $1 AND u.deleted_at IS NULL)::bigint AS following_count
+`
+
+type GetFollowCountsByUserIDRow struct {
+	FollowerCount  int64 `json:"follower_count"`
+	FollowingCount int64 `json:"following_count"`
+}
+
+func (q *Queries) GetFollowCountsByUserID(ctx context.Context, userID pgtype.UUID) (GetFollowCountsByUserIDRow, error) {
+	row := q.db.QueryRow(ctx, getFollowCountsByUserID, userID)
+	var i GetFollowCountsByUserIDRow
+	err := row.Scan(&i.FollowerCount, &i.FollowingCount)
+	return i, err
+}
+
+const getFollowersByUserID = `-- name: GetFollowersByUserID :many
+SELECT u.id, u.username, u.email, u.description, u.avatar_url, u.created_at, u.updated_at, u.display_name, u.website_url, u.github_url, u.twitter_url, u.linkedin_url, u.location, u.custom_avatar_url, u.show_email, u.role, u.suspended_at, u.deleted_at
+FROM user_follows uf
+JOIN users u ON u.id = uf.follower_id
+WHERE uf.followee_id = $1
+AND u.deleted_at IS NULL
+ORDER BY uf.created_at DESC
+LIMIT $3 OFFSET $2
+`
+
+type GetFollowersByUserIDParams struct {
+	UserID pgtype.UUID `json:"user_id"`
+	Offset int32       `json:"offset"`
+	Limit  int32       `json:"limit"`
+}
+
+func (q *Queries) GetFollowersByUserID(ctx context.Context, arg GetFollowersByUserIDParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, getFollowersByUserID, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.Description,
+			&i.AvatarUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DisplayName,
+			&i.WebsiteUrl,
+			&i.GithubUrl,
+			&i.TwitterUrl,
+			&i.LinkedinUrl,
+			&i.Location,
+			&i.CustomAvatarUrl,
+			&i.ShowEmail,
+			&i.Role,
+			&i.SuspendedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollowingByUserID = `-- name: GetFollowingByUserID :many
+SELECT u.id, u.username, u.email, u.description, u.avatar_url, u.created_at, u.updated_at, u.display_name, u.website_url, u.github_url, u.twitter_url, u.linkedin_url, u.location, u.custom_avatar_url, u.show_email, u.role, u.suspended_at, u.deleted_at
+FROM user_follows uf
+JOIN users u ON u.id = uf.followee_id
+WHERE uf.follower_id = $1
+AND u.deleted_at IS NULL
+ORDER BY uf.created_at DESC
+LIMIT $3 OFFSET $2
 `
 
-func (q *Queries) GetUserByGoogleID(ctx context.Context, googleID string) (User, error) {
-	row := q.db.QueryRow(ctx, getUserByGoogleID, googleID)
+type GetFollowingByUserIDParams struct {
+	UserID pgtype.UUID `json:"user_id"`
+	Offset int32       `json:"offset"`
+	Limit  int32       `json:"limit"`
+}
+
+func (q *Queries) GetFollowingByUserID(ctx context.Context, arg GetFollowingByUserIDParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, getFollowingByUserID, arg.UserID, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.Description,
+			&i.AvatarUrl,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DisplayName,
+			&i.WebsiteUrl,
+			&i.GithubUrl,
+			&i.TwitterUrl,
+			&i.LinkedinUrl,
+			&i.Location,
+			&i.CustomAvatarUrl,
+			&i.ShowEmail,
+			&i.Role,
+			&i.SuspendedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at FROM users 
+WHERE lower(email) = lower($1)
+`
+
+// Includes deleted accounts, so signing in again can reactivate them.
+func (q *Queries) GetUserByEmail(ctx context.Context, lower string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, lower)
 	var i User
 	err := row.Scan(
 		&i.ID,
-		&i.GoogleID,
 		&i.Username,
 		&i.Email,
 		&i.Description,
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, google_id, username, email, description, avatar_url, created_at, updated_at FROM users 
-WHERE id = $1
+SELECT id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at FROM users 
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error) {
@@ -85,20 +344,30 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 	var i User
 	err := row.Scan(
 		&i.ID,
-		&i.GoogleID,
 		&i.Username,
 		&i.Email,
 		&i.Description,
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, google_id, username, email, description, avatar_url, created_at, updated_at FROM users 
-WHERE username = $1
+SELECT id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at FROM users 
+WHERE username = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username pgtype.Text) (User, error) {
@@ -106,21 +375,31 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username pgtype.Text) (
 	var i User
 	err := row.Scan(
 		&i.ID,
-		&i.GoogleID,
 		&i.Username,
 		&i.Email,
 		&i.Description,
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, google_id, username, email, description, avatar_url, created_at, updated_at
+SELECT id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
 FROM users
-WHERE id = ANY($1::uuid[])
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUsersByIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]User, error) {
@@ -134,13 +413,23 @@ func (q *Queries) GetUsersByIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]
 		var i User
 		if err := rows.Scan(
 			&i.ID,
-			&i.GoogleID,
 			&i.Username,
 			&i.Email,
 			&i.Description,
 			&i.AvatarUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DisplayName,
+			&i.WebsiteUrl,
+			&i.GithubUrl,
+			&i.TwitterUrl,
+			&i.LinkedinUrl,
+			&i.Location,
+			&i.CustomAvatarUrl,
+			&i.ShowEmail,
+			&i.Role,
+			&i.SuspendedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -152,30 +441,398 @@ func (q *Queries) GetUsersByIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]
 	return items, nil
 }
 
+const hasAdmin = `-- name: HasAdmin :one
+SELECT EXISTS (
+    SELECT 1 FROM users WHERE role = 'admin' AND deleted_at IS NULL
+) AS has_admin
+`
+
+func (q *Queries) HasAdmin(ctx context.Context) (bool, error) {
+	row := q.db.QueryRow(ctx, hasAdmin)
+	var has_admin bool
+	err := row.Scan(&has_admin)
+	return has_admin, err
+}
+
+const isBlockedByUser = `-- name: IsBlockedByUser :one
+SELECT EXISTS (
+    SELECT 1 FROM user_blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+) AS is_blocked
+`
+
+type IsBlockedByUserParams struct {
+	BlockerID pgtype.UUID `json:"blocker_id"`
+	BlockedID pgtype.UUID `json:"blocked_id"`
+}
+
+func (q *Queries) IsBlockedByUser(ctx context.Context, arg IsBlockedByUserParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isBlockedByUser, arg.BlockerID, arg.BlockedID)
+	var is_blocked bool
+	err := row.Scan(&is_blocked)
+	return is_blocked, err
+}
+
+const isFollowingUser = `-- name: IsFollowingUser :one
+SELECT EXISTS (
+    SELECT 1 FROM user_follows
+    WHERE follower_id = $1 AND followee_id = $2
+) AS is_following
+`
+
+type IsFollowingUserParams struct {
+	FollowerID pgtype.UUID `json:"follower_id"`
+	FolloweeID pgtype.UUID `json:"followee_id"`
+}
+
+func (q *Queries) IsFollowingUser(ctx context.Context, arg IsFollowingUserParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isFollowingUser, arg.FollowerID, arg.FolloweeID)
+	var is_following bool
+	err := row.Scan(&is_following)
+	return is_following, err
+}
+
+const isUsernameTaken = `-- name: IsUsernameTaken :one
+SELECT (
+    EXISTS (SELECT 1 FROM users WHERE lower(username) = lower($1) AND id IS DISTINCT FROM $2)
+    OR EXISTS (SELECT 1 FROM username_history WHERE lower(username) = lower($1) AND user_id IS DISTINCT FROM $2)
+)::boolean AS is_taken
+`
+
+type IsUsernameTakenParams struct {
+	Username string      `json:"username"`
+	UserID   pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) IsUsernameTaken(ctx context.Context, arg IsUsernameTakenParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isUsernameTaken, arg.Username, arg.UserID)
+	var is_taken bool
+	err := row.Scan(&is_taken)
+	return is_taken, err
+}
+
+const muteUser = `-- name: MuteUser :exec
+INSERT INTO user_mutes (muter_id, muted_id)
+VALUES ($1, $2)
+ON CONFLICT (muter_id, muted_id) DO NOTHING
+`
+
+type MuteUserParams struct {
+	MuterID pgtype.UUID `json:"muter_id"`
+	MutedID pgtype.UUID `json:"muted_id"`
+}
+
+func (q *Queries) MuteUser(ctx context.Context, arg MuteUserParams) error {
+	_, err := q.db.Exec(ctx, muteUser, arg.MuterID, arg.MutedID)
+	return err
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedUsers, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :exec
+UPDATE users
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, softDeleteUser, id)
+	return err
+}
+
+const suspendUser = `-- name: SuspendUser :one
+UPDATE users
+SET suspended_at = now()
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
+`
+
+func (q *Queries) SuspendUser(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, suspendUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const unblockUser = `-- name: UnblockUser :exec
+DELETE FROM user_blocks
+WHERE blocker_id = $1 AND blocked_id = $2
+`
+
+type UnblockUserParams struct {
+	BlockerID pgtype.UUID `json:"blocker_id"`
+	BlockedID pgtype.UUID `json:"blocked_id"`
+}
+
+func (q *Queries) UnblockUser(ctx context.Context, arg UnblockUserParams) error {
+	_, err := q.db.Exec(ctx, unblockUser, arg.BlockerID, arg.BlockedID)
+	return err
+}
+
+const unfollowUser = `-- name: UnfollowUser :exec
+DELETE FROM user_follows
+WHERE follower_id = $1 AND followee_id = $2
+`
+
+type UnfollowUserParams struct {
+	FollowerID pgtype.UUID `json:"follower_id"`
+	FolloweeID pgtype.UUID `json:"followee_id"`
+}
+
+func (q *Queries) UnfollowUser(ctx context.Context, arg UnfollowUserParams) error {
+	_, err := q.db.Exec(ctx, unfollowUser, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const unmuteUser = `-- name: UnmuteUser :exec
+DELETE FROM user_mutes
+WHERE muter_id = $1 AND muted_id = $2
+`
+
+type UnmuteUserParams struct {
+	MuterID pgtype.UUID `json:"muter_id"`
+	MutedID pgtype.UUID `json:"muted_id"`
+}
+
+func (q *Queries) UnmuteUser(ctx context.Context, arg UnmuteUserParams) error {
+	_, err := q.db.Exec(ctx, unmuteUser, arg.MuterID, arg.MutedID)
+	return err
+}
+
+const unsuspendUser = `-- name: UnsuspendUser :one
+UPDATE users
+SET suspended_at = NULL
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
+`
+
+func (q *Queries) UnsuspendUser(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, unsuspendUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
-SET description = $2, updated_at = now()
+SET description = $2,
+    display_name = $3,
+    website_url = $4,
+    github_url = $5,
+    twitter_url = $6,
+    linkedin_url = $7,
+    location = $8,
+    custom_avatar_url = $9,
+    show_email = $10,
+    updated_at = now()
 WHERE id = $1
-RETURNING id, google_id, username, email, description, avatar_url, created_at, updated_at
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
 `
 
 type UpdateUserParams struct {
-	ID          pgtype.UUID `json:"id"`
-	Description pgtype.Text `json:"description"`
+	ID              pgtype.UUID `json:"id"`
+	Description     pgtype.Text `json:"description"`
+	DisplayName     pgtype.Text `json:"display_name"`
+	WebsiteUrl      pgtype.Text `json:"website_url"`
+	GithubUrl       pgtype.Text `json:"github_url"`
+	TwitterUrl      pgtype.Text `json:"twitter_url"`
+	LinkedinUrl     pgtype.Text `json:"linkedin_url"`
+	Location        pgtype.Text `json:"location"`
+	CustomAvatarUrl pgtype.Text `json:"custom_avatar_url"`
+	ShowEmail       bool        `json:"show_email"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, updateUser, arg.ID, arg.Description)
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.ID,
+		arg.Description,
+		arg.DisplayName,
+		arg.WebsiteUrl,
+		arg.GithubUrl,
+		arg.TwitterUrl,
+		arg.LinkedinUrl,
+		arg.Location,
+		arg.CustomAvatarUrl,
+		arg.ShowEmail,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
+`
+
+type UpdateUserRoleParams struct {
+	ID   pgtype.UUID `json:"id"`
+	Role string      `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Description,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateUsername = `-- name: UpdateUsername :one
+UPDATE users
+SET username = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, username, email, description, avatar_url, created_at, updated_at, display_name, website_url, github_url, twitter_url, linkedin_url, location, custom_avatar_url, show_email, role, suspended_at, deleted_at
+`
+
+type UpdateUsernameParams struct {
+	ID       pgtype.UUID `json:"id"`
+	Username pgtype.Text `json:"username"`
+}
+
+func (q *Queries) UpdateUsername(ctx context.Context, arg UpdateUsernameParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUsername, arg.ID, arg.Username)
 	var i User
 	err := row.Scan(
 		&i.ID,
-		&i.GoogleID,
 		&i.Username,
 		&i.Email,
 		&i.Description,
 		&i.AvatarUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DisplayName,
+		&i.WebsiteUrl,
+		&i.GithubUrl,
+		&i.TwitterUrl,
+		&i.LinkedinUrl,
+		&i.Location,
+		&i.CustomAvatarUrl,
+		&i.ShowEmail,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/neevan0842/BlogSphere/backend/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	googleIssuer  = "https://accounts.google.com"
+	googleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+	githubAPIURL  = "https://api.github.com"
+)
+
+// newProviders returns the login providers that have OAuth credentials configured, keyed by name
+func newProviders() map[string]Provider {
+	providers := make(map[string]Provider)
+
+	if config.Envs.GOOGLE_CLIENT_ID != "" {
+		providers["google"] = &googleProvider{
+			oauthConfig: &oauth2.Config{
+				ClientID:     config.Envs.GOOGLE_CLIENT_ID,
+				ClientSecret: config.Envs.GOOGLE_CLIENT_SECRET,
+				RedirectURL:  config.Envs.GOOGLE_REDIRECT_URI,
+				Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+				Endpoint:     google.Endpoint,
+			},
+			verifier: oidc.NewVerifier(
+				googleIssuer,
+				oidc.NewRemoteKeySet(context.Background(), googleJWKSURL),
+				&oidc.Config{ClientID: config.Envs.GOOGLE_CLIENT_ID},
+			),
+		}
+	}
+
+	if config.Envs.GITHUB_CLIENT_ID != "" {
+		providers["github"] = &githubProvider{
+			oauthConfig: &oauth2.Config{
+				ClientID:     config.Envs.GITHUB_CLIENT_ID,
+				ClientSecret: config.Envs.GITHUB_CLIENT_SECRET,
+				RedirectURL:  config.Envs.GITHUB_REDIRECT_URI,
+				Scopes:       []string{"read:user", "user:email"},
+				Endpoint:     github.Endpoint,
+			},
+			apiURL: githubAPIURL,
+		}
+	}
+
+	return providers
+}
+
+type googleProvider struct {
+	oauthConfig *oauth2.Config
+	verifier    *oidc.IDTokenVerifier
+}
+
+func (p *googleProvider) Name() string {
+	return "google"
+}
+
+func (p *googleProvider) AuthCodeURL(flow AuthFlow) string {
+	return p.oauthConfig.AuthCodeURL(flow.State,
+		oauth2.S256ChallengeOption(flow.Verifier),
+		oauth2.SetAuthURLParam("nonce", flow.Nonce),
+	)
+}
+
+// FetchUser reads the user from the ID token returned with the access token, after checking
+// its signature, issuer, audience and expiry and that it carries this login's nonce
+func (p *googleProvider) FetchUser(ctx context.Context, code string, flow AuthFlow) (ProviderUser, error) {
+	token, err := p.oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		return ProviderUser{}, fmt.Errorf("code exchange wrong: %s", err.Error())
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return ProviderUser{}, fmt.Errorf("no id_token in token response")
+	}
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return ProviderUser{}, fmt.Errorf("failed to verify id token: %s", err.Error())
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(flow.Nonce)) != 1 {
+		return ProviderUser{}, fmt.Errorf("id token nonce mismatch")
+	}
+
+	var claims GoogleIDTokenClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return ProviderUser{}, fmt.Errorf("failed to parse id token claims: %s", err.Error())
+	}
+	if !claims.EmailVerified {
+		return ProviderUser{}, ErrEmailNotVerified
+	}
+
+	// Extract username from email (part before @)
+	username := claims.Email
+	if atIndex := strings.Index(claims.Email, "@"); atIndex != -1 {
+		username = claims.Email[:atIndex]
+	}
+
+	return ProviderUser{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Username:      username,
+		AvatarURL:     claims.Picture,
+	}, nil
+}
+
+type githubProvider struct {
+	oauthConfig *oauth2.Config
+	apiURL      string
+}
+
+func (p *githubProvider) Name() string {
+	return "github"
+}
+
+func (p *githubProvider) AuthCodeURL(flow AuthFlow) string {
+	return p.oauthConfig.AuthCodeURL(flow.State, oauth2.S256ChallengeOption(flow.Verifier))
+}
+
+// FetchUser redeems the code with the PKCE verifier. GitHub is not an OpenID provider,
+// so there is no ID token or nonce and the user is read from the REST API instead.
+func (p *githubProvider) FetchUser(ctx context.Context, code string, flow AuthFlow) (ProviderUser, error) {
+	token, err := p.oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		return ProviderUser{}, fmt.Errorf("code exchange wrong: %s", err.Error())
+	}
+	client := p.oauthConfig.Client(ctx, token)
+
+	var githubUser GithubUserResponse
+	if err := getJSON(client, p.apiURL+"/user", &githubUser); err != nil {
+		return ProviderUser{}, fmt.Errorf("failed getting user info: %s", err.Error())
+	}
+
+	// the profile email is optional and unverified, so use the primary verified address instead
+	var emails []GithubEmailResponse
+	if err := getJSON(client, p.apiURL+"/user/emails", &emails); err != nil {
+		return ProviderUser{}, fmt.Errorf("failed getting user emails: %s", err.Error())
+	}
+
+	user := ProviderUser{
+		Subject:   strconv.FormatInt(githubUser.ID, 10),
+		Username:  githubUser.Login,
+		AvatarURL: githubUser.AvatarURL,
+	}
+	for _, email := range emails {
+		if email.Primary {
+			user.Email = email.Email
+			user.EmailVerified = email.Verified
+			break
+		}
+	}
+	return user, nil
+}
+
+// getJSON fetches url with the authenticated client and decodes the JSON response into v
+func getJSON(client *http.Client, url string, v any) error {
+	response, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	contents, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed read response: %s", err.Error())
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, contents)
+	}
+
+	if err := json.Unmarshal(contents, v); err != nil {
+		return fmt.Errorf("failed to parse response: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,413 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testClientID    = "test-client"
+	testRedirectURL = "http://localhost/auth/callback"
+	testKeyID       = "test-key"
+)
+
+// fakeOAuthServer is an OAuth provider with authorize, token, JWKS and GitHub-style user
+// endpoints. Codes are bound to the PKCE challenge and nonce they were authorized with.
+type fakeOAuthServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]fakeGrant // code -> grant
+
+	// identity returned for the next logins
+	subject       string
+	email         string
+	emailVerified bool
+	githubEmails  []GithubEmailResponse
+
+	// tokenNonce, when set, replaces the authorized nonce in issued ID tokens
+	tokenNonce string
+}
+
+type fakeGrant struct {
+	challenge string
+	nonce     string
+}
+
+func newFakeOAuthServer(t *testing.T) *fakeOAuthServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s := &fakeOAuthServer{
+		key:           key,
+		grants:        make(map[string]fakeGrant),
+		subject:       "subject-1",
+		email:         "jane.doe@example.com",
+		emailVerified: true,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /authorize", s.handleAuthorize)
+	mux.HandleFunc("POST /token", s.handleToken)
+	mux.HandleFunc("GET /keys", s.handleKeys)
+	mux.HandleFunc("GET /user", s.handleGithubUser)
+	mux.HandleFunc("GET /user/emails", s.handleGithubEmails)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeOAuthServer) endpoint() oauth2.Endpoint {
+	return oauth2.Endpoint{
+		AuthURL:  s.URL + "/authorize",
+		TokenURL: s.URL + "/token",
+	}
+}
+
+func (s *fakeOAuthServer) googleProvider() *googleProvider {
+	return &googleProvider{
+		oauthConfig: &oauth2.Config{
+			ClientID:     testClientID,
+			ClientSecret: "test-secret",
+			RedirectURL:  testRedirectURL,
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+			Endpoint:     s.endpoint(),
+		},
+		verifier: oidc.NewVerifier(
+			s.URL,
+			oidc.NewRemoteKeySet(context.Background(), s.URL+"/keys"),
+			&oidc.Config{ClientID: testClientID},
+		),
+	}
+}
+
+func (s *fakeOAuthServer) githubProvider() *githubProvider {
+	return &githubProvider{
+		oauthConfig: &oauth2.Config{
+			ClientID:     testClientID,
+			ClientSecret: "test-secret",
+			RedirectURL:  testRedirectURL,
+			Scopes:       []string{"read:user", "user:email"},
+			Endpoint:     s.endpoint(),
+		},
+		apiURL: s.URL,
+	}
+}
+
+// authorize plays the browser: it follows the provider's authorization URL and returns
+// the code and state the provider redirects back with
+func (s *fakeOAuthServer) authorize(t *testing.T, authURL string) (string, string) {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	response, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d, want %d", response.StatusCode, http.StatusFound)
+	}
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize: bad redirect: %v", err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func (s *fakeOAuthServer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != testClientID || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomToken()
+	s.mu.Lock()
+	s.grants[code] = fakeGrant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	s.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *fakeOAuthServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	grant, ok := s.grants[r.FormValue("code")]
+	delete(s.grants, r.FormValue("code"))
+	s.mu.Unlock()
+
+	// the code is only redeemed with the verifier whose challenge it was issued for
+	if !ok || oauth2.S256ChallengeFromVerifier(r.FormValue("code_verifier")) != grant.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	nonce := grant.nonce
+	if s.tokenNonce != "" {
+		nonce = s.tokenNonce
+	}
+	now := time.Now()
+	idToken := s.signJWT(map[string]any{
+		"iss":            s.URL,
+		"aud":            testClientID,
+		"sub":            s.subject,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          s.email,
+		"email_verified": s.emailVerified,
+		"picture":        "https://example.com/avatar.png",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "access-" + s.subject,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (s *fakeOAuthServer) handleKeys(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *fakeOAuthServer) handleGithubUser(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+	json.NewEncoder(w).Encode(GithubUserResponse{ID: 42, Login: "octocat", AvatarURL: "https://example.com/octocat.png"})
+}
+
+func (s *fakeOAuthServer) handleGithubEmails(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(w, r) {
+		return
+	}
+	json.NewEncoder(w).Encode(s.githubEmails)
+}
+
+func (s *fakeOAuthServer) authorized(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("Authorization") != "Bearer access-"+s.subject {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// signJWT signs claims with the server's RS256 key
+func (s *fakeOAuthServer) signJWT(claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": testKeyID, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newTestFlow() AuthFlow {
+	return AuthFlow{
+		State:    randomToken(),
+		Verifier: oauth2.GenerateVerifier(),
+		Nonce:    randomToken(),
+	}
+}
+
+func TestGoogleProviderAuthCodeURL(t *testing.T) {
+	server := newFakeOAuthServer(t)
+	flow := newTestFlow()
+
+	authURL, err := url.Parse(server.googleProvider().AuthCodeURL(flow))
+	if err != nil {
+		t.Fatalf("parse auth URL: %v", err)
+	}
+	query := authURL.Query()
+	if got := query.Get("state"); got != flow.State {
+		t.Errorf("state = %q, want %q", got, flow.State)
+	}
+	if got := query.Get("nonce"); got != flow.Nonce {
+		t.Errorf("nonce = %q, want %q", got, flow.Nonce)
+	}
+	if got, want := query.Get("code_challenge"), oauth2.S256ChallengeFromVerifier(flow.Verifier); got != want {
+		t.Errorf("code_challenge = %q, want %q", got, want)
+	}
+	if got := query.Get("code_challenge_method"); got != "S256" {
+		t.Errorf("code_challenge_method = %q, want S256", got)
+	}
+}
+
+func TestGoogleProviderFetchUser(t *testing.T) {
+	server := newFakeOAuthServer(t)
+	provider := server.googleProvider()
+	flow := newTestFlow()
+
+	code, _ := server.authorize(t, provider.AuthCodeURL(flow))
+	user, err := provider.FetchUser(context.Background(), code, flow)
+	if err != nil {
+		t.Fatalf("FetchUser: %v", err)
+	}
+
+	want := ProviderUser{
+		Subject:       "subject-1",
+		Email:         "jane.doe@example.com",
+		EmailVerified: true,
+		Username:      "jane.doe",
+		AvatarURL:     "https://example.com/avatar.png",
+	}
+	if user != want {
+		t.Errorf("FetchUser = %+v, want %+v", user, want)
+	}
+}
+
+func TestGoogleProviderFetchUserErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(server *fakeOAuthServer, flow *AuthFlow)
+		wantErr  error
+		wantText string
+	}{
+		{
+			name: "nonce mismatch",
+			setup: func(server *fakeOAuthServer, flow *AuthFlow) {
+				server.tokenNonce = "nonce-from-another-login"
+			},
+			wantText: "nonce mismatch",
+		},
+		{
+			name: "wrong PKCE verifier",
+			setup: func(server *fakeOAuthServer, flow *AuthFlow) {
+				flow.Verifier = oauth2.GenerateVerifier()
+			},
+			wantText: "code exchange",
+		},
+		{
+			name: "unverified email",
+			setup: func(server *fakeOAuthServer, flow *AuthFlow) {
+				server.emailVerified = false
+			},
+			wantErr: ErrEmailNotVerified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeOAuthServer(t)
+			provider := server.googleProvider()
+			flow := newTestFlow()
+
+			code, _ := server.authorize(t, provider.AuthCodeURL(flow))
+			tt.setup(server, &flow)
+
+			_, err := provider.FetchUser(context.Background(), code, flow)
+			if err == nil {
+				t.Fatal("FetchUser succeeded, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("FetchUser error = %v, want %v", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("FetchUser error = %q, want it to mention %q", err, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestGithubProviderFetchUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		emails       []GithubEmailResponse
+		wantEmail    string
+		wantVerified bool
+	}{
+		{
+			name: "primary verified email",
+			emails: []GithubEmailResponse{
+				{Email: "old@example.com", Primary: false, Verified: true},
+				{Email: "octocat@example.com", Primary: true, Verified: true},
+			},
+			wantEmail:    "octocat@example.com",
+			wantVerified: true,
+		},
+		{
+			name: "primary email not verified",
+			emails: []GithubEmailResponse{
+				{Email: "other@example.com", Primary: false, Verified: true},
+				{Email: "octocat@example.com", Primary: true, Verified: false},
+			},
+			wantEmail:    "octocat@example.com",
+			wantVerified: false,
+		},
+		{
+			name: "no primary email",
+			emails: []GithubEmailResponse{
+				{Email: "other@example.com", Primary: false, Verified: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeOAuthServer(t)
+			server.githubEmails = tt.emails
+			provider := server.githubProvider()
+			flow := newTestFlow()
+
+			authURL := provider.AuthCodeURL(flow)
+			if strings.Contains(authURL, "nonce=") {
+				t.Errorf("GitHub auth URL carries a nonce: %s", authURL)
+			}
+			code, _ := server.authorize(t, authURL)
+
+			user, err := provider.FetchUser(context.Background(), code, flow)
+			if err != nil {
+				t.Fatalf("FetchUser: %v", err)
+			}
+			if user.Subject != "42" || user.Username != "octocat" {
+				t.Errorf("FetchUser subject, username = %q, %q, want %q, %q", user.Subject, user.Username, "42", "octocat")
+			}
+			if user.Email != tt.wantEmail || user.EmailVerified != tt.wantVerified {
+				t.Errorf("FetchUser email = %q (verified %t), want %q (verified %t)", user.Email, user.EmailVerified, tt.wantEmail, tt.wantVerified)
+			}
+		})
+	}
+}
@@ -1,81 +1,113 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/neevan0842/BlogSphere/backend/config"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
 	"github.com/neevan0842/BlogSphere/backend/mailer"
 	"github.com/neevan0842/BlogSphere/backend/utils"
 	"go.uber.org/zap"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 )
 
 type handler struct {
-	service Service
-	logger  *zap.SugaredLogger
-	mail    *mailer.Mailer
-}
-
-var googleOauthConfig = &oauth2.Config{
-	ClientID:     config.Envs.GOOGLE_CLIENT_ID,
-	ClientSecret: config.Envs.GOOGLE_CLIENT_SECRET,
-	RedirectURL:  config.Envs.GOOGLE_REDIRECT_URI,
-	Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"},
-	Endpoint:     google.Endpoint,
+	service   Service
+	logger    *zap.SugaredLogger
+	mail      *mailer.Mailer
+	providers map[string]Provider
 }
 
 func NewHandler(service Service, logger *zap.SugaredLogger, mail *mailer.Mailer) *handler {
 	return &handler{
-		service: service,
-		logger:  logger,
-		mail:    mail,
+		service:   service,
+		logger:    logger,
+		mail:      mail,
+		providers: newProviders(),
 	}
 }
 
-func (h *handler) HandleGoogleLogin(w http.ResponseWriter, r *http.Request) {
-	// Create oauthState cookie
-	oauthState := h.service.generateStateOauthCookie(w)
-	url := googleOauthConfig.AuthCodeURL(oauthState)
+func (h *handler) HandleOAuthLogin(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.providers[chi.URLParam(r, "provider")]
+	if !ok {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("unknown login provider"))
+		return
+	}
+
+	// Store the state, PKCE verifier and nonce in the oauthstate cookie
+	flow := h.service.startOAuthFlow(w)
+	url := provider.AuthCodeURL(flow)
 	utils.WriteJSON(w, http.StatusOK, map[string]string{
 		"url": url,
 	})
 }
 
-func (h *handler) HandleGoogleAuthCallback(w http.ResponseWriter, r *http.Request) {
-	// Read oauthState from Cookie
-	oauthState, err := r.Cookie("oauthstate")
+func (h *handler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.providers[chi.URLParam(r, "provider")]
+	if !ok {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("unknown login provider"))
+		return
+	}
+	authErr := fmt.Errorf("could not authenticate with %s", provider.Name())
 
+	// Read the login flow from the oauthstate cookie
+	flow, err := h.service.readOAuthFlow(r)
 	if err != nil {
-		h.logger.Errorf("could not read oauthstate cookie: %s", err.Error())
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("could not authenticate with google"))
+		h.logger.Errorf("%s login: %s", provider.Name(), err.Error())
+		utils.WriteError(w, http.StatusUnauthorized, authErr)
 		return
 	}
 
-	if r.FormValue("state") != oauthState.Value {
-		h.logger.Error("invalid oauth google state")
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("could not authenticate with google"))
+	// Exchange the code for the provider's user info
+	providerUser, err := provider.FetchUser(r.Context(), r.FormValue("code"), flow)
+	if errors.Is(err, ErrEmailNotVerified) {
+		utils.WriteError(w, http.StatusUnauthorized, err)
 		return
 	}
-
-	// Convert to createUserParams
-	userData, err := h.service.getUserDataFromGoogle(r.FormValue("code"))
 	if err != nil {
 		h.logger.Error(err.Error())
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("could not authenticate with google"))
+		utils.WriteError(w, http.StatusUnauthorized, authErr)
 		return
 	}
 
-	user, isNewUser, err := h.service.createUserIfNotExists(r.Context(), userData)
+	user, isNewUser, err := h.service.findOrCreateUser(r.Context(), provider.Name(), providerUser)
+	if errors.Is(err, ErrEmailNotVerified) {
+		utils.WriteError(w, http.StatusUnauthorized, err)
+		return
+	}
 	if err != nil {
 		h.logger.Error(err.Error())
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("could not authenticate with google"))
+		utils.WriteError(w, http.StatusUnauthorized, authErr)
+		return
+	}
+	// refuse suspended accounts before reactivating, so a deleted and suspended account stays deleted
+	if user.SuspendedAt.Valid {
+		utils.WriteError(w, http.StatusForbidden, common.ErrAccountSuspended)
+		return
+	}
+	if user.DeletedAt.Valid {
+		user, err = h.service.reactivateUser(r.Context(), user)
+		if errors.Is(err, ErrAccountDeleted) {
+			utils.WriteError(w, http.StatusForbidden, err)
+			return
+		}
+		if err != nil {
+			h.logger.Error(err.Error())
+			utils.WriteError(w, http.StatusUnauthorized, authErr)
+			return
+		}
+	}
+	user, err = h.service.promoteConfiguredAdmin(r.Context(), user, isNewUser)
+	if err != nil {
+		h.logger.Error(err.Error())
+		utils.WriteError(w, http.StatusUnauthorized, authErr)
 		return
 	}
 
-	// Generate JWT tokens
-	accessToken, refreshToken, err := utils.GetAccessAndRefreshTokens(user.ID.String())
+	// Start a session and generate JWT tokens
+	accessToken, refreshToken, err := h.service.createSession(r.Context(), user.ID, r.UserAgent(), utils.GetClientIP(r))
 	if err != nil {
 		h.logger.Error(err.Error())
 		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("could not generate tokens"))
@@ -115,31 +147,75 @@ func (h *handler) HandleRefresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// get userID from refresh token
-	userIDUUID, err := utils.GetUserIDFromToken(w, payload.RefreshToken)
+	// rotate the refresh token
+	accessToken, refreshToken, err := h.service.refreshSession(r.Context(), payload.RefreshToken)
 	if err != nil {
-		h.logger.Errorf("invalid userID in refresh token: %s", err.Error())
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+		h.logger.Errorf("failed to refresh session: %s", err.Error())
+		if errors.Is(err, common.ErrAccountSuspended) {
+			utils.WriteError(w, http.StatusForbidden, err)
+			return
+		}
+		if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenReused) {
+			utils.WriteError(w, http.StatusUnauthorized, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("could not refresh tokens"))
 		return
 	}
 
-	// check if user exists in database
-	_, err = h.service.GetUserByID(r.Context(), userIDUUID)
+	utils.WriteJSON(w, http.StatusOK, map[string]string{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    fmt.Sprintf("%d minutes", config.Envs.ACCESS_TOKEN_EXPIRE_MINUTES),
+	})
+}
+
+func (h *handler) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	var payload RefreshRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request body"))
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request body"))
+		return
+	}
+
+	err := h.service.revokeSession(r.Context(), payload.RefreshToken)
+	if errors.Is(err, ErrInvalidToken) {
+		utils.WriteError(w, http.StatusUnauthorized, err)
+		return
+	}
 	if err != nil {
-		h.logger.Errorf("failed to get user from database: %s", err.Error())
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+		h.logger.Error(err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("could not log out"))
 		return
 	}
-	// Generate new access token
-	accessToken, _, err := utils.GetAccessAndRefreshTokens(userIDUUID.String())
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) HandleLogoutAll(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by authentication middleware)
+	userID, _ := utils.GetUserIDFromContext(r.Context())
+	userIDUUID, err := utils.StrToUUID(userID)
 	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID in token: %s", err.Error()))
+		return
+	}
+
+	if err := h.service.revokeAllSessions(r.Context(), userIDUUID); err != nil {
 		h.logger.Error(err.Error())
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("could not generate access token"))
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("could not log out"))
 		return
 	}
-	utils.WriteJSON(w, http.StatusOK, map[string]string{
-		"access_token": accessToken,
-		"token_type":   "Bearer",
-		"expires_in":   fmt.Sprintf("%d minutes", config.Envs.ACCESS_TOKEN_EXPIRE_MINUTES),
-	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleJWKS publishes the public keys other services can verify our tokens with
+func (h *handler) HandleJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	utils.WriteJSON(w, http.StatusOK, utils.GetJWKS())
 }
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"go.uber.org/zap"
+)
+
+// callbackService runs the real OAuth flow handling and records the provider user instead
+// of storing it, so the callback can be tested without a database
+type callbackService struct {
+	*svc
+	providerUsers []ProviderUser
+}
+
+func (s *callbackService) findOrCreateUser(ctx context.Context, provider string, providerUser ProviderUser) (sqlc.User, bool, error) {
+	s.providerUsers = append(s.providerUsers, providerUser)
+	return sqlc.User{ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}}, false, nil
+}
+
+func (s *callbackService) createSession(ctx context.Context, userID pgtype.UUID, userAgent string, ipAddress string) (string, string, error) {
+	return "access-token", "refresh-token", nil
+}
+
+func newCallbackRouter(service Service, providers map[string]Provider) http.Handler {
+	h := &handler{
+		service:   service,
+		logger:    zap.NewNop().Sugar(),
+		providers: providers,
+	}
+	r := chi.NewRouter()
+	r.Get("/auth/{provider}", h.HandleOAuthLogin)
+	r.Get("/auth/{provider}/callback", h.HandleOAuthCallback)
+	return r
+}
+
+// startLogin requests the login URL and returns it along with the oauthstate cookie
+func startLogin(t *testing.T, router http.Handler, provider string) (string, *http.Cookie) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auth/"+provider, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("login: status %d, body %s", recorder.Code, recorder.Body)
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatalf("login: decode body: %v", err)
+	}
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == "oauthstate" {
+			return body["url"], cookie
+		}
+	}
+	t.Fatal("login: no oauthstate cookie set")
+	return "", nil
+}
+
+func callback(router http.Handler, provider, code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	query := url.Values{"code": {code}, "state": {state}}
+	request := httptest.NewRequest(http.MethodGet, "/auth/"+provider+"/callback?"+query.Encode(), nil)
+	if cookie != nil {
+		request.AddCookie(cookie)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestOAuthCallback(t *testing.T) {
+	server := newFakeOAuthServer(t)
+	service := &callbackService{svc: &svc{}}
+	router := newCallbackRouter(service, map[string]Provider{"google": server.googleProvider()})
+
+	loginURL, cookie := startLogin(t, router, "google")
+	code, state := server.authorize(t, loginURL)
+
+	// the state, verifier and nonce travel in the cookie and must match what the provider saw
+	parts := strings.Split(cookie.Value, ".")
+	if len(parts) != 3 || parts[0] != state {
+		t.Fatalf("oauthstate cookie %q does not carry state %q", cookie.Value, state)
+	}
+
+	recorder := callback(router, "google", code, state, cookie)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("callback: status %d, body %s", recorder.Code, recorder.Body)
+	}
+
+	var tokens map[string]string
+	if err := json.NewDecoder(recorder.Body).Decode(&tokens); err != nil {
+		t.Fatalf("callback: decode body: %v", err)
+	}
+	if tokens["access_token"] != "access-token" || tokens["refresh_token"] != "refresh-token" {
+		t.Errorf("callback tokens = %v", tokens)
+	}
+
+	if len(service.providerUsers) != 1 {
+		t.Fatalf("findOrCreateUser called %d times, want 1", len(service.providerUsers))
+	}
+	if user := service.providerUsers[0]; user.Subject != "subject-1" || user.Email != "jane.doe@example.com" || !user.EmailVerified {
+		t.Errorf("findOrCreateUser got %+v", user)
+	}
+
+	cleared := false
+	for _, c := range recorder.Result().Cookies() {
+		if c.Name == "oauthstate" && c.Value == "" {
+			cleared = true
+		}
+	}
+	if !cleared {
+		t.Error("callback did not clear the oauthstate cookie")
+	}
+}
+
+func TestOAuthCallbackRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(server *fakeOAuthServer)
+		tamper    func(state string, cookie *http.Cookie) (string, *http.Cookie)
+		wantError string
+	}{
+		{
+			name: "state mismatch",
+			tamper: func(state string, cookie *http.Cookie) (string, *http.Cookie) {
+				return "forged-state", cookie
+			},
+		},
+		{
+			name: "missing cookie",
+			tamper: func(state string, cookie *http.Cookie) (string, *http.Cookie) {
+				return state, nil
+			},
+		},
+		{
+			name: "cookie from another login",
+			tamper: func(state string, cookie *http.Cookie) (string, *http.Cookie) {
+				other := newTestFlow()
+				return other.State, &http.Cookie{Name: "oauthstate", Value: other.State + "." + other.Verifier + "." + other.Nonce}
+			},
+		},
+		{
+			name: "nonce mismatch",
+			setup: func(server *fakeOAuthServer) {
+				server.tokenNonce = "nonce-from-another-login"
+			},
+		},
+		{
+			name: "unverified google email",
+			setup: func(server *fakeOAuthServer) {
+				server.emailVerified = false
+			},
+			wantError: ErrEmailNotVerified.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeOAuthServer(t)
+			if tt.setup != nil {
+				tt.setup(server)
+			}
+			service := &callbackService{svc: &svc{}}
+			router := newCallbackRouter(service, map[string]Provider{"google": server.googleProvider()})
+
+			loginURL, cookie := startLogin(t, router, "google")
+			code, state := server.authorize(t, loginURL)
+			if tt.tamper != nil {
+				state, cookie = tt.tamper(state, cookie)
+			}
+
+			recorder := callback(router, "google", code, state, cookie)
+			if recorder.Code != http.StatusUnauthorized {
+				t.Fatalf("callback: status %d, want %d; body %s", recorder.Code, http.StatusUnauthorized, recorder.Body)
+			}
+			if tt.wantError != "" && !strings.Contains(recorder.Body.String(), tt.wantError) {
+				t.Errorf("callback body %s, want error %q", recorder.Body, tt.wantError)
+			}
+			if len(service.providerUsers) != 0 {
+				t.Errorf("findOrCreateUser was called with %+v", service.providerUsers)
+			}
+		})
+	}
+}
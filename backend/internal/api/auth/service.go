@@ -3,17 +3,23 @@ package auth
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neevan0842/BlogSphere/backend/config"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
 	"github.com/neevan0842/BlogSphere/backend/utils"
+	"golang.org/x/oauth2"
 )
 
 type svc struct {
@@ -21,8 +27,6 @@ type svc struct {
 	db   *pgxpool.Pool
 }
 
-const oauthGoogleUrlAPI = "https://www.googleapis.com/oauth2/v2/userinfo?access_token="
-
 func NewService(repo *sqlc.Queries, db *pgxpool.Pool) Service {
 	return &svc{
 		repo: repo,
@@ -30,57 +34,174 @@ func NewService(repo *sqlc.Queries, db *pgxpool.Pool) Service {
 	}
 }
 
-func (s *svc) generateStateOauthCookie(w http.ResponseWriter) string {
+// startOAuthFlow creates the state, PKCE verifier and nonce for a login. They are stored together
+// in the oauthstate cookie, so a state is only accepted with the verifier it was issued with.
+func (s *svc) startOAuthFlow(w http.ResponseWriter) AuthFlow {
 	var expirationInMinutes int64 = 10
-	b := make([]byte, 16)
-	rand.Read(b)
-	state := base64.URLEncoding.EncodeToString(b)
-	utils.SetCookie(w, "oauthstate", state, expirationInMinutes)
-	return state
+	flow := AuthFlow{
+		State:    randomToken(),
+		Verifier: oauth2.GenerateVerifier(),
+		Nonce:    randomToken(),
+	}
+	utils.SetCookie(w, "oauthstate", strings.Join([]string{flow.State, flow.Verifier, flow.Nonce}, "."), expirationInMinutes)
+	return flow
 }
 
-func (s *svc) getUserDataFromGoogle(code string) (sqlc.CreateUserParams, error) {
-	// Use code to get token and get user info from Google.
-
-	token, err := googleOauthConfig.Exchange(context.Background(), code)
+// readOAuthFlow returns the login flow from the oauthstate cookie if the callback's state matches it
+func (s *svc) readOAuthFlow(r *http.Request) (AuthFlow, error) {
+	cookie, err := r.Cookie("oauthstate")
 	if err != nil {
-		return sqlc.CreateUserParams{}, fmt.Errorf("code exchange wrong: %s", err.Error())
+		return AuthFlow{}, fmt.Errorf("could not read oauthstate cookie: %s", err.Error())
 	}
-	response, err := http.Get(oauthGoogleUrlAPI + token.AccessToken)
-	if err != nil {
-		return sqlc.CreateUserParams{}, fmt.Errorf("failed getting user info: %s", err.Error())
+
+	parts := strings.Split(cookie.Value, ".")
+	if len(parts) != 3 {
+		return AuthFlow{}, fmt.Errorf("malformed oauthstate cookie")
 	}
-	defer response.Body.Close()
-	contents, err := io.ReadAll(response.Body)
-	if err != nil {
-		return sqlc.CreateUserParams{}, fmt.Errorf("failed read response: %s", err.Error())
+	flow := AuthFlow{State: parts[0], Verifier: parts[1], Nonce: parts[2]}
+
+	if subtle.ConstantTimeCompare([]byte(r.FormValue("state")), []byte(flow.State)) != 1 {
+		return AuthFlow{}, fmt.Errorf("invalid oauth state")
+	}
+	return flow, nil
+}
+
+// findOrCreateUser returns the user a provider identity belongs to. An identity seen for the first
+// time is linked to the user with the same verified email, or gets a new user when there is none.
+func (s *svc) findOrCreateUser(ctx context.Context, provider string, providerUser ProviderUser) (sqlc.User, bool, error) {
+	existingUser, err := s.repo.GetUserByIdentity(ctx, sqlc.GetUserByIdentityParams{
+		Provider: provider,
+		Subject:  providerUser.Subject,
+	})
+	if err == nil {
+		return existingUser, false, nil // identity already linked
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.User{}, false, fmt.Errorf("failed to get user by identity: %s", err.Error())
+	}
+
+	// an unverified email could claim someone else's account
+	if providerUser.Email == "" || !providerUser.EmailVerified {
+		return sqlc.User{}, false, ErrEmailNotVerified
 	}
 
-	// parse google user data
-	data, err := parseGoogleUserData(contents)
+	var user sqlc.User
+	isNewUser := false
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		user, err = q.GetUserByEmail(ctx, providerUser.Email)
+		if errors.Is(err, pgx.ErrNoRows) {
+			username, err := availableUsername(ctx, q, providerUser.Username)
+			if err != nil {
+				return err
+			}
+			user, err = q.CreateUser(ctx, sqlc.CreateUserParams{
+				Username: username,
+				Email:    providerUser.Email,
+				AvatarUrl: pgtype.Text{
+					String: providerUser.AvatarURL,
+					Valid:  providerUser.AvatarURL != "",
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create user: %s", err.Error())
+			}
+			isNewUser = true
+		} else if err != nil {
+			return fmt.Errorf("failed to get user by email: %s", err.Error())
+		}
+
+		_, err = q.CreateUserIdentity(ctx, sqlc.CreateUserIdentityParams{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  providerUser.Subject,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to link identity: %s", err.Error())
+		}
+		return nil
+	})
 	if err != nil {
-		return sqlc.CreateUserParams{}, fmt.Errorf("failed to parse user data: %s", err.Error())
+		return sqlc.User{}, false, err
 	}
+	return user, isNewUser, nil
+}
 
-	// convert to CreateUserParams
-	CreateUserParams := convertToCreateUserParams(data)
+// reactivateUser restores a deleted account that signs in again within the restore window,
+// along with the posts and comments that were deleted with it. Suspended accounts are left deleted.
+func (s *svc) reactivateUser(ctx context.Context, user sqlc.User) (sqlc.User, error) {
+	if user.SuspendedAt.Valid {
+		return sqlc.User{}, common.ErrAccountSuspended
+	}
+	if user.DeletedAt.Time.Before(common.RestoreCutoff().Time) {
+		return sqlc.User{}, ErrAccountDeleted
+	}
 
-	return CreateUserParams, nil
+	var restored sqlc.User
+	err := common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		if err := q.RestorePostsByAuthorID(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to restore posts: %s", err.Error())
+		}
+		if err := q.RestoreCommentsByUserID(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to restore comments: %s", err.Error())
+		}
+		var err error
+		restored, err = q.RestoreUser(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to restore user: %s", err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return sqlc.User{}, err
+	}
+	return restored, nil
 }
 
-func (s *svc) createUserIfNotExists(ctx context.Context, arg sqlc.CreateUserParams) (sqlc.User, bool, error) {
-	// Check if user already exists
-	existingUser, err := s.repo.GetUserByGoogleID(ctx, arg.GoogleID)
-	if err == nil {
-		return existingUser, false, nil // user already exists
+// promoteConfiguredAdmin makes the user an admin when their email is listed in ADMIN_EMAILS,
+// which is how the first admin is set up. Only new users are promoted, or existing ones while
+// there is no admin yet, so a role changed later is never overwritten on sign-in.
+// The change is recorded as made by the user.
+func (s *svc) promoteConfiguredAdmin(ctx context.Context, user sqlc.User, isNewUser bool) (sqlc.User, error) {
+	if user.Role == utils.RoleAdmin || !isAdminEmail(user.Email) {
+		return user, nil
 	}
 
-	// Create new user
-	user, err := s.repo.CreateUser(ctx, arg)
+	promoted := user
+	err := common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		if !isNewUser {
+			hasAdmin, err := q.HasAdmin(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to check for an admin: %s", err.Error())
+			}
+			if hasAdmin {
+				return nil
+			}
+		}
+
+		var err error
+		promoted, err = q.UpdateUserRole(ctx, sqlc.UpdateUserRoleParams{
+			ID:   user.ID,
+			Role: utils.RoleAdmin,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to promote admin: %s", err.Error())
+		}
+		return common.RecordModerationAction(ctx, q, user.ID, common.ModerationActionChangeRole, common.ModerationTargetUser, user.ID, "listed in ADMIN_EMAILS")
+	})
 	if err != nil {
-		return sqlc.User{}, false, fmt.Errorf("failed to create user: %s", err.Error())
+		return sqlc.User{}, err
+	}
+	return promoted, nil
+}
+
+// isAdminEmail reports whether email is in the comma-separated ADMIN_EMAILS list
+func isAdminEmail(email string) bool {
+	for _, adminEmail := range strings.Split(config.Envs.ADMIN_EMAILS, ",") {
+		if adminEmail = strings.TrimSpace(adminEmail); adminEmail != "" && strings.EqualFold(adminEmail, email) {
+			return true
+		}
 	}
-	return user, true, nil // new user created
+	return false
 }
 
 func (s *svc) GetUserByID(ctx context.Context, userID pgtype.UUID) (sqlc.User, error) {
@@ -91,32 +212,160 @@ func (s *svc) GetUserByID(ctx context.Context, userID pgtype.UUID) (sqlc.User, e
 	return user, nil
 }
 
-func parseGoogleUserData(data []byte) (*GoogleUserResponse, error) {
-	var googleUser GoogleUserResponse
-	err := json.Unmarshal(data, &googleUser)
+// createSession starts a new session for a signed-in user and returns its first access and refresh tokens
+func (s *svc) createSession(ctx context.Context, userID pgtype.UUID, userAgent string, ipAddress string) (string, string, error) {
+	var accessToken, refreshToken string
+	err := common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		session, err := q.CreateSession(ctx, sqlc.CreateSessionParams{
+			UserID:    userID,
+			UserAgent: userAgent,
+			IpAddress: ipAddress,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create session: %s", err.Error())
+		}
+		accessToken, refreshToken, err = issueTokens(ctx, q, userID, session.ID)
+		return err
+	})
+	return accessToken, refreshToken, err
+}
+
+// refreshSession exchanges a refresh token for a new token pair. Each refresh token can be
+// used once; presenting a used one means it was leaked, so the whole session is revoked.
+// Sessions of deleted users are revoked and those of suspended users are refused.
+func (s *svc) refreshSession(ctx context.Context, refreshToken string) (string, string, error) {
+	claims, err := utils.ParseToken(refreshToken, utils.TokenTypeRefresh)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+
+	var accessToken, newRefreshToken string
+	reused, deleted := false, false
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		token, err := q.GetRefreshTokenByHash(ctx, utils.HashTokenID(claims.TokenID))
+		if err != nil {
+			return ErrInvalidToken
+		}
+
+		if token.UsedAt.Valid {
+			// revoke in this transaction and report the reuse once it has committed
+			reused = true
+			if err := q.RevokeSession(ctx, token.SessionID); err != nil {
+				return fmt.Errorf("failed to revoke session: %s", err.Error())
+			}
+			return nil
+		}
+
+		session, err := q.GetSessionByID(ctx, token.SessionID)
+		if err != nil || session.RevokedAt.Valid || time.Now().After(token.ExpiresAt.Time) {
+			return ErrInvalidToken
+		}
+
+		user, err := q.GetUserByID(ctx, session.UserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// the account was deleted after the session started
+			deleted = true
+			if err := q.RevokeSession(ctx, session.ID); err != nil {
+				return fmt.Errorf("failed to revoke session: %s", err.Error())
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get user: %s", err.Error())
+		}
+		if user.SuspendedAt.Valid {
+			return common.ErrAccountSuspended
+		}
+
+		if err := q.MarkRefreshTokenUsed(ctx, token.ID); err != nil {
+			return fmt.Errorf("failed to rotate refresh token: %s", err.Error())
+		}
+		if err := q.TouchSession(ctx, session.ID); err != nil {
+			return fmt.Errorf("failed to update session: %s", err.Error())
+		}
+		accessToken, newRefreshToken, err = issueTokens(ctx, q, session.UserID, session.ID)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if reused {
+		return "", "", ErrTokenReused
+	}
+	if deleted {
+		return "", "", ErrInvalidToken
+	}
+	return accessToken, newRefreshToken, nil
+}
+
+// revokeSession ends the session the refresh token belongs to
+func (s *svc) revokeSession(ctx context.Context, refreshToken string) error {
+	claims, err := utils.ParseToken(refreshToken, utils.TokenTypeRefresh)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	token, err := s.repo.GetRefreshTokenByHash(ctx, utils.HashTokenID(claims.TokenID))
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if err := s.repo.RevokeSession(ctx, token.SessionID); err != nil {
+		return fmt.Errorf("failed to revoke session: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *svc) revokeAllSessions(ctx context.Context, userID pgtype.UUID) error {
+	if err := s.repo.RevokeSessionsByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %s", err.Error())
+	}
+	return nil
+}
+
+// issueTokens stores a new refresh token for the session and signs the token pair
+func issueTokens(ctx context.Context, q *sqlc.Queries, userID pgtype.UUID, sessionID pgtype.UUID) (string, string, error) {
+	tokenID := uuid.NewString()
+	expiresAt := time.Now().Add(time.Minute * time.Duration(config.Envs.REFRESH_TOKEN_EXPIRE_MINUTES))
+
+	_, err := q.CreateRefreshToken(ctx, sqlc.CreateRefreshTokenParams{
+		SessionID: sessionID,
+		TokenHash: utils.HashTokenID(tokenID),
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse user data: %s", err.Error())
+		return "", "", fmt.Errorf("failed to store refresh token: %s", err.Error())
 	}
-	return &googleUser, nil
+
+	return utils.GetAccessAndRefreshTokens(userID.String(), sessionID.String(), tokenID)
 }
 
-func convertToCreateUserParams(googleUser *GoogleUserResponse) sqlc.CreateUserParams {
-	// Extract username from email (part before @)
-	username := googleUser.Email
-	if atIndex := strings.Index(googleUser.Email, "@"); atIndex != -1 {
-		username = googleUser.Email[:atIndex]
+// availableUsername turns the provider's suggested username into a valid one and, when
+// another user already has it, appends a random suffix until it is unique
+func availableUsername(ctx context.Context, q *sqlc.Queries, suggested string) (pgtype.Text, error) {
+	base := utils.NormalizeUsername(suggested)
+	if !utils.IsValidUsername(base) {
+		base = "user"
 	}
 
-	return sqlc.CreateUserParams{
-		GoogleID: googleUser.ID,
-		Username: pgtype.Text{
-			String: username,
-			Valid:  username != "",
-		},
-		Email: googleUser.Email,
-		AvatarUrl: pgtype.Text{
-			String: googleUser.Picture,
-			Valid:  googleUser.Picture != "",
-		},
+	username := base
+	for attempt := 0; attempt < 5; attempt++ {
+		taken, err := q.IsUsernameTaken(ctx, sqlc.IsUsernameTakenParams{Username: username})
+		if err != nil {
+			return pgtype.Text{}, fmt.Errorf("failed to check username: %s", err.Error())
+		}
+		if !taken {
+			return pgtype.Text{String: username, Valid: true}, nil
+		}
+		// leave room for the suffix within the maximum length
+		trimmed := base[:min(len(base), utils.UsernameMaxLength-9)]
+		username = trimmed + "-" + uuid.NewString()[:8]
 	}
+	return pgtype.Text{}, fmt.Errorf("no available username for %q", suggested)
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
 }
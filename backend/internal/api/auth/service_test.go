@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neevan0842/BlogSphere/backend/config"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+// newTestService returns a service backed by a fresh schema in the database at
+// TEST_DATABASE_URL with every migration applied. Tests are skipped without one.
+func newTestService(t *testing.T) *svc {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer admin.Close(ctx)
+
+	schema := fmt.Sprintf("auth_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(context.Background(), databaseURL)
+		if err != nil {
+			return
+		}
+		defer conn.Close(context.Background())
+		conn.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		t.Fatalf("parse database URL: %v", err)
+	}
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrations, err := filepath.Glob("../../../database/migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		contents, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("read %s: %v", migration, err)
+		}
+		if _, err := pool.Exec(ctx, string(contents)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(migration), err)
+		}
+	}
+
+	return &svc{repo: sqlc.New(pool), db: pool}
+}
+
+func createTestUser(t *testing.T, s *svc, username, email string) sqlc.User {
+	t.Helper()
+	user, err := s.repo.CreateUser(context.Background(), sqlc.CreateUserParams{
+		Username: pgtype.Text{String: username, Valid: true},
+		Email:    email,
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func TestFindOrCreateUserCreatesUser(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	user, isNewUser, err := s.findOrCreateUser(ctx, "google", ProviderUser{
+		Subject:       "google-1",
+		Email:         "new@example.com",
+		EmailVerified: true,
+		Username:      "New.User",
+	})
+	if err != nil {
+		t.Fatalf("findOrCreateUser: %v", err)
+	}
+	if !isNewUser || user.Email != "new@example.com" || user.Username.String != "new-user" {
+		t.Errorf("findOrCreateUser = %s %q (new %t), want new user new-user", user.Email, user.Username.String, isNewUser)
+	}
+
+	// signing in again finds the same user through the identity
+	again, isNewUser, err := s.findOrCreateUser(ctx, "google", ProviderUser{Subject: "google-1"})
+	if err != nil {
+		t.Fatalf("findOrCreateUser again: %v", err)
+	}
+	if isNewUser || again.ID != user.ID {
+		t.Errorf("second sign-in returned user %s (new %t), want %s", again.ID, isNewUser, user.ID)
+	}
+}
+
+func TestFindOrCreateUserLinksVerifiedEmail(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	existing := createTestUser(t, s, "octocat", "octocat@example.com")
+
+	user, isNewUser, err := s.findOrCreateUser(ctx, "github", ProviderUser{
+		Subject:       "42",
+		Email:         "octocat@example.com",
+		EmailVerified: true,
+		Username:      "octocat",
+	})
+	if err != nil {
+		t.Fatalf("findOrCreateUser: %v", err)
+	}
+	if isNewUser || user.ID != existing.ID {
+		t.Errorf("findOrCreateUser returned user %s (new %t), want existing user %s", user.ID, isNewUser, existing.ID)
+	}
+
+	linked, err := s.repo.GetUserByIdentity(ctx, sqlc.GetUserByIdentityParams{Provider: "github", Subject: "42"})
+	if err != nil {
+		t.Fatalf("identity was not linked: %v", err)
+	}
+	if linked.ID != existing.ID {
+		t.Errorf("identity linked to %s, want %s", linked.ID, existing.ID)
+	}
+}
+
+func TestFindOrCreateUserRejectsUnverifiedEmail(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerUser ProviderUser
+	}{
+		{
+			name:         "unverified email",
+			providerUser: ProviderUser{Subject: "42", Email: "victim@example.com", EmailVerified: false, Username: "attacker"},
+		},
+		{
+			name:         "no email",
+			providerUser: ProviderUser{Subject: "42", Username: "attacker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			ctx := context.Background()
+			createTestUser(t, s, "victim", "victim@example.com")
+
+			_, _, err := s.findOrCreateUser(ctx, "github", tt.providerUser)
+			if !errors.Is(err, ErrEmailNotVerified) {
+				t.Fatalf("findOrCreateUser error = %v, want %v", err, ErrEmailNotVerified)
+			}
+
+			_, err = s.repo.GetUserByIdentity(ctx, sqlc.GetUserByIdentityParams{Provider: "github", Subject: "42"})
+			if !errors.Is(err, pgx.ErrNoRows) {
+				t.Errorf("identity was linked to the existing account (err = %v)", err)
+			}
+		})
+	}
+}
+
+func TestIsAdminEmail(t *testing.T) {
+	adminEmails := config.Envs.ADMIN_EMAILS
+	t.Cleanup(func() { config.Envs.ADMIN_EMAILS = adminEmails })
+	config.Envs.ADMIN_EMAILS = " owner@example.com, ,Ops@Example.com"
+
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"owner@example.com", true},
+		{"ops@example.com", true},
+		{"someone@example.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isAdminEmail(tt.email); got != tt.want {
+			t.Errorf("isAdminEmail(%q) = %t, want %t", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestPromoteConfiguredAdmin(t *testing.T) {
+	adminEmails := config.Envs.ADMIN_EMAILS
+	t.Cleanup(func() { config.Envs.ADMIN_EMAILS = adminEmails })
+	config.Envs.ADMIN_EMAILS = "owner@example.com"
+
+	tests := []struct {
+		name      string
+		isNewUser bool
+		hasAdmin  bool
+		want      string
+	}{
+		{name: "new user", isNewUser: true, hasAdmin: true, want: utils.RoleAdmin},
+		{name: "existing user without an admin", isNewUser: false, hasAdmin: false, want: utils.RoleAdmin},
+		{name: "existing user with an admin", isNewUser: false, hasAdmin: true, want: utils.RoleUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			ctx := context.Background()
+			if tt.hasAdmin {
+				other := createTestUser(t, s, "other", "other@example.com")
+				if _, err := s.repo.UpdateUserRole(ctx, sqlc.UpdateUserRoleParams{ID: other.ID, Role: utils.RoleAdmin}); err != nil {
+					t.Fatalf("update role: %v", err)
+				}
+			}
+			user := createTestUser(t, s, "owner", "owner@example.com")
+
+			got, err := s.promoteConfiguredAdmin(ctx, user, tt.isNewUser)
+			if err != nil {
+				t.Fatalf("promoteConfiguredAdmin: %v", err)
+			}
+			if got.Role != tt.want {
+				t.Errorf("role = %q, want %q", got.Role, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -9,22 +10,71 @@ import (
 )
 
 type Service interface {
-	generateStateOauthCookie(w http.ResponseWriter) string
-	getUserDataFromGoogle(code string) (sqlc.CreateUserParams, error)
-	createUserIfNotExists(ctx context.Context, arg sqlc.CreateUserParams) (sqlc.User, bool, error)
+	startOAuthFlow(w http.ResponseWriter) AuthFlow
+	readOAuthFlow(r *http.Request) (AuthFlow, error)
+	findOrCreateUser(ctx context.Context, provider string, providerUser ProviderUser) (sqlc.User, bool, error)
+	reactivateUser(ctx context.Context, user sqlc.User) (sqlc.User, error)
+	promoteConfiguredAdmin(ctx context.Context, user sqlc.User, isNewUser bool) (sqlc.User, error)
 	GetUserByID(ctx context.Context, userID pgtype.UUID) (sqlc.User, error)
+	createSession(ctx context.Context, userID pgtype.UUID, userAgent string, ipAddress string) (string, string, error)
+	refreshSession(ctx context.Context, refreshToken string) (string, string, error)
+	revokeSession(ctx context.Context, refreshToken string) error
+	revokeAllSessions(ctx context.Context, userID pgtype.UUID) error
 }
 
+// Provider is an OAuth login provider such as Google or GitHub
+type Provider interface {
+	Name() string
+	AuthCodeURL(flow AuthFlow) string
+	FetchUser(ctx context.Context, code string, flow AuthFlow) (ProviderUser, error)
+}
+
+// AuthFlow holds the per-login secrets: the state echoed back on the callback, the PKCE
+// code verifier the code is redeemed with, and the nonce the ID token must carry.
+type AuthFlow struct {
+	State    string
+	Verifier string
+	Nonce    string
+}
+
+// ProviderUser is the account a provider reports for the user who signed in
+type ProviderUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	AvatarURL     string
+}
+
+var (
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrTokenReused      = errors.New("refresh token reuse detected, session revoked")
+	ErrEmailNotVerified = errors.New("a verified email address is required to sign in")
+	ErrAccountDeleted   = errors.New("this account has been deleted and can no longer be restored")
+)
+
 type RefreshRequest struct {
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
-type GoogleUserResponse struct {
-	ID            string `json:"id"`
+// GoogleIDTokenClaims are the profile claims of a Google ID token
+type GoogleIDTokenClaims struct {
 	Email         string `json:"email"`
-	VerifiedEmail bool   `json:"verified_email"`
+	EmailVerified bool   `json:"email_verified"`
 	Name          string `json:"name"`
-	GivenName     string `json:"given_name"`
-	FamilyName    string `json:"family_name"`
 	Picture       string `json:"picture"`
 }
+
+type GithubUserResponse struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type GithubEmailResponse struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
@@ -1,10 +1,14 @@
 package comments
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
 	"github.com/neevan0842/BlogSphere/backend/utils"
 	"go.uber.org/zap"
 )
@@ -39,7 +43,23 @@ func (h *handler) HandleCreateComment(w http.ResponseWriter, r *http.Request) {
 	// Get requesting user ID (set by authentication middleware)
 	userID, _ := utils.GetUserIDFromContext(r.Context())
 
-	comment, err := h.service.CreateComment(r.Context(), payload.PostID, userID, payload.Body)
+	comment, err := h.service.CreateComment(r.Context(), payload.PostID, userID, payload.Body, payload.ParentID)
+	if errors.Is(err, ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, ErrInvalidParentComment) {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	if errors.Is(err, common.ErrBlocked) {
+		utils.WriteError(w, http.StatusForbidden, err)
+		return
+	}
+	if errors.Is(err, contentfilter.ErrRejected) {
+		utils.WriteError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -50,29 +70,56 @@ func (h *handler) HandleCreateComment(w http.ResponseWriter, r *http.Request) {
 
 func (h *handler) HandleDeleteComment(w http.ResponseWriter, r *http.Request) {
 	commentID := chi.URLParam(r, "commentID")
-	commentIDUUID, _ := utils.StrToUUID(commentID)
 
-	// Get user ID from context (set by authentication middleware)
-	userID, _ := utils.GetUserIDFromContext(r.Context())
+	// Get user from context (set by authentication middleware)
+	user, _ := utils.GetUserFromContext(r.Context())
 
-	comment, err := h.repo.GetCommentByID(r.Context(), commentIDUUID)
-	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+	err := h.service.DeleteComment(r.Context(), commentID, user)
+	switch {
+	case errors.Is(err, ErrCommentNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrNotCommentAuthor):
+		utils.PermissionDenied(w)
+		return
+	case err != nil:
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	// Only allow comment author to delete the comment
-	if comment.UserID.String() != userID {
-		utils.WriteError(w, http.StatusForbidden, err)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) HandleHideComment(w http.ResponseWriter, r *http.Request) {
+	h.handleSetHidden(w, r, true)
+}
+
+func (h *handler) HandleUnhideComment(w http.ResponseWriter, r *http.Request) {
+	h.handleSetHidden(w, r, false)
+}
+
+// handleSetHidden hides or unhides a comment. Only moderators are routed here.
+func (h *handler) handleSetHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	commentID := chi.URLParam(r, "commentID")
+	moderator, _ := utils.GetUserFromContext(r.Context())
+
+	reason, err := common.ReadModerationReason(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := h.service.DeleteComment(r.Context(), commentID); err != nil {
+	comment, err := h.service.SetCommentHidden(r.Context(), commentID, moderator.ID, hidden, reason)
+	switch {
+	case errors.Is(err, ErrCommentNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case err != nil:
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	utils.WriteJSON(w, http.StatusOK, comment)
 }
 
 func (h *handler) HandleUpdateComment(w http.ResponseWriter, r *http.Request) {
@@ -106,7 +153,17 @@ func (h *handler) HandleUpdateComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// deleted placeholders cannot be edited
+	if comment.IsDeleted {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("comment has been deleted"))
+		return
+	}
+
 	updatedComment, err := h.service.UpdateComment(r.Context(), commentIDUUID, payload.Body)
+	if errors.Is(err, contentfilter.ErrRejected) {
+		utils.WriteError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -114,3 +171,28 @@ func (h *handler) HandleUpdateComment(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJSON(w, http.StatusOK, updatedComment)
 }
+
+// toggle like/unlike a comment
+func (h *handler) HandleCommentLikes(w http.ResponseWriter, r *http.Request) {
+	commentID := chi.URLParam(r, "commentID")
+	userID, _ := utils.GetUserIDFromContext(r.Context())
+
+	commentIDUUID, err := utils.StrToUUID(commentID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid comment ID: %s", err.Error()))
+		return
+	}
+	userIDUUID, _ := utils.StrToUUID(userID)
+
+	userHasLiked, err := h.service.toggleCommentLike(r.Context(), commentIDUUID, userIDUUID)
+	if errors.Is(err, ErrCommentNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to toggle comment like: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]bool{"liked": userHasLiked})
+}
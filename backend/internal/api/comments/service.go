@@ -2,39 +2,85 @@ package comments
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
 	"github.com/neevan0842/BlogSphere/backend/utils"
 )
 
 type svc struct {
-	repo *sqlc.Queries
-	db   *pgxpool.Pool
+	repo   *sqlc.Queries
+	db     *pgxpool.Pool
+	filter *contentfilter.Pipeline
 }
 
-func NewService(repo *sqlc.Queries, db *pgxpool.Pool) Service {
+// NewService creates the comments service. New and edited comments go through the content
+// filter, which can reject them or hide them until a moderator approves them.
+func NewService(repo *sqlc.Queries, db *pgxpool.Pool, filter *contentfilter.Pipeline) Service {
 	return &svc{
-		repo: repo,
-		db:   db,
+		repo:   repo,
+		db:     db,
+		filter: filter,
 	}
 }
 
-func (s *svc) CreateComment(ctx context.Context, postID string, userID string, body string) (common.CommentDTO, error) {
+func (s *svc) CreateComment(ctx context.Context, postID string, userID string, body string, parentID string) (common.CommentDTO, error) {
 	postIDUUID, _ := utils.StrToUUID(postID)
 	userIDUUID, _ := utils.StrToUUID(userID)
 
-	comment, err := s.repo.CreateComment(ctx, sqlc.CreateCommentParams{
-		PostID: postIDUUID,
-		UserID: userIDUUID,
-		Body:   body,
+	// comments can only be left on posts visible to the user
+	post, err := s.repo.GetPostByID(ctx, postIDUUID)
+	if err != nil || !common.CanViewPost(post, &userIDUUID) {
+		return common.CommentDTO{}, ErrPostNotFound
+	}
+	if err := common.CheckNotBlocked(ctx, s.repo, post.AuthorID, userIDUUID); err != nil {
+		return common.CommentDTO{}, err
+	}
+
+	// replies must point at a live comment on the same post
+	var parentIDUUID pgtype.UUID
+	if parentID != "" {
+		parentIDUUID, _ = utils.StrToUUID(parentID)
+		parent, err := s.repo.GetCommentByID(ctx, parentIDUUID)
+		if err != nil {
+			return common.CommentDTO{}, ErrInvalidParentComment
+		}
+		if parent.PostID != postIDUUID || parent.IsDeleted {
+			return common.CommentDTO{}, ErrInvalidParentComment
+		}
+	}
+
+	content, verdict, err := s.checkContent(ctx, pgtype.UUID{}, userIDUUID, body)
+	if err != nil {
+		return common.CommentDTO{}, err
+	}
+
+	var comment sqlc.Comment
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		comment, err = q.CreateComment(ctx, sqlc.CreateCommentParams{
+			PostID:   postIDUUID,
+			UserID:   userIDUUID,
+			Body:     body,
+			ParentID: parentIDUUID,
+		})
+		if err != nil {
+			return err
+		}
+		comment, err = holdComment(ctx, q, comment, content, verdict)
+		return err
 	})
+	if err != nil {
+		return common.CommentDTO{}, err
+	}
 
 	// enrich comment with author details
-	comments, err := common.EnrichCommentsWithAuthors(ctx, s.repo, []sqlc.Comment{comment})
+	comments, err := common.EnrichCommentsWithAuthors(ctx, s.repo, []sqlc.Comment{comment}, &userIDUUID)
 	if err != nil {
 		return common.CommentDTO{}, err
 	}
@@ -46,23 +92,130 @@ func (s *svc) CreateComment(ctx context.Context, postID string, userID string, b
 	return comments[0], err
 }
 
-func (s *svc) DeleteComment(ctx context.Context, commentID string) error {
-	commentIDUUID, _ := utils.StrToUUID(commentID)
+// DeleteComment removes a comment on behalf of its author or a moderator, recording
+// deletions by a moderator in the moderation log. A comment that still has replies is
+// replaced by a placeholder instead, so the thread below it survives. Removing the last
+// reply of a placeholder also removes the placeholder, walking up the thread as far as needed.
+func (s *svc) DeleteComment(ctx context.Context, commentID string, user sqlc.User) error {
+	commentIDUUID, err := utils.StrToUUID(commentID)
+	if err != nil {
+		return ErrCommentNotFound
+	}
+
+	target, err := s.repo.GetCommentByID(ctx, commentIDUUID)
+	if err != nil {
+		return ErrCommentNotFound
+	}
+
+	isAuthor := target.UserID == user.ID
+	if !isAuthor && !utils.CanModerate(user.Role) {
+		return ErrNotCommentAuthor
+	}
+
+	return common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		if !isAuthor {
+			err := common.RecordModerationAction(ctx, q, user.ID, common.ModerationActionDelete, common.ModerationTargetComment, target.ID, "")
+			if err != nil {
+				return err
+			}
+		}
+
+		id := target.ID
+		for {
+			comment, err := q.GetCommentByID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get comment: %s", err.Error())
+			}
+
+			replyCount, err := q.CountRepliesByCommentID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to count replies: %s", err.Error())
+			}
+
+			if replyCount > 0 {
+				if comment.IsDeleted {
+					return nil
+				}
+				if _, err := q.TombstoneComment(ctx, id); err != nil {
+					return fmt.Errorf("failed to delete comment: %s", err.Error())
+				}
+				return nil
+			}
+
+			if err := q.SoftDeleteComment(ctx, id); err != nil {
+				return fmt.Errorf("failed to delete comment: %s", err.Error())
+			}
+
+			// clean up a placeholder parent that no longer has any replies
+			if !comment.ParentID.Valid {
+				return nil
+			}
+			parent, err := q.GetCommentByID(ctx, comment.ParentID)
+			if err != nil {
+				return fmt.Errorf("failed to get parent comment: %s", err.Error())
+			}
+			if !parent.IsDeleted {
+				return nil
+			}
+			id = parent.ID
+		}
+	})
+}
+
+// SetCommentHidden hides a comment's body and author from everyone, or shows it again, and records the action
+func (s *svc) SetCommentHidden(ctx context.Context, commentID string, moderatorID pgtype.UUID, hidden bool, reason string) (common.CommentDTO, error) {
+	commentIDUUID, err := utils.StrToUUID(commentID)
+	if err != nil {
+		return common.CommentDTO{}, ErrCommentNotFound
+	}
+
+	var comment sqlc.Comment
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		comment, err = common.SetCommentHidden(ctx, q, moderatorID, commentIDUUID, hidden, reason)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return common.CommentDTO{}, err
+	}
 
-	return s.repo.DeleteComment(ctx, commentIDUUID)
+	comments, err := common.EnrichCommentsWithAuthors(ctx, s.repo, []sqlc.Comment{comment}, &moderatorID)
+	if err != nil {
+		return common.CommentDTO{}, err
+	}
+	return comments[0], nil
 }
 
 func (s *svc) UpdateComment(ctx context.Context, commentID pgtype.UUID, body string) (common.CommentDTO, error) {
-	comment, err := s.repo.UpdateComment(ctx, sqlc.UpdateCommentParams{
-		ID:   commentID,
-		Body: body,
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return common.CommentDTO{}, ErrCommentNotFound
+	}
+
+	content, verdict, err := s.checkContent(ctx, commentID, comment.UserID, body)
+	if err != nil {
+		return common.CommentDTO{}, err
+	}
+
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		comment, err = q.UpdateComment(ctx, sqlc.UpdateCommentParams{
+			ID:   commentID,
+			Body: body,
+		})
+		if err != nil {
+			return err
+		}
+		comment, err = holdComment(ctx, q, comment, content, verdict)
+		return err
 	})
 	if err != nil {
 		return common.CommentDTO{}, err
 	}
 
 	// enrich comment with author details
-	comments, err := common.EnrichCommentsWithAuthors(ctx, s.repo, []sqlc.Comment{comment})
+	comments, err := common.EnrichCommentsWithAuthors(ctx, s.repo, []sqlc.Comment{comment}, &comment.UserID)
 	if err != nil {
 		return common.CommentDTO{}, err
 	}
@@ -73,3 +226,76 @@ func (s *svc) UpdateComment(ctx context.Context, commentID pgtype.UUID, body str
 
 	return comments[0], nil
 }
+
+func (s *svc) toggleCommentLike(ctx context.Context, commentID pgtype.UUID, userID pgtype.UUID) (bool, error) {
+	// only live comments on posts visible to the user can be liked; deleted and
+	// hidden comments are shown as placeholders
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil || comment.IsDeleted || comment.IsHidden {
+		return false, ErrCommentNotFound
+	}
+	post, err := s.repo.GetPostByID(ctx, comment.PostID)
+	if err != nil || !common.CanViewPost(post, &userID) {
+		return false, ErrCommentNotFound
+	}
+
+	// Check if user has already liked the comment
+	_, err = s.repo.GetCommentLike(ctx, sqlc.GetCommentLikeParams{
+		CommentID: commentID,
+		UserID:    userID,
+	})
+
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("failed to check existing like: %s", err.Error())
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		// User has not liked the comment, so add like
+		_, err := s.repo.CreateCommentLike(ctx, sqlc.CreateCommentLikeParams{
+			CommentID: commentID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to like comment: %s", err.Error())
+		}
+		return true, nil // Comment is now liked
+	} else {
+		// User has already liked the comment, so remove like
+		err := s.repo.DeleteCommentLike(ctx, sqlc.DeleteCommentLikeParams{
+			CommentID: commentID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to unlike comment: %s", err.Error())
+		}
+		return false, nil // Comment is now unliked
+	}
+}
+
+// checkContent runs a comment body through the content filter. Rejected comments return
+// contentfilter.ErrRejected; held comments are hidden by holdComment when they are saved.
+func (s *svc) checkContent(ctx context.Context, commentID pgtype.UUID, authorID pgtype.UUID, body string) (contentfilter.Content, contentfilter.Verdict, error) {
+	content := contentfilter.Content{
+		TargetType: common.ModerationTargetComment,
+		TargetID:   commentID,
+		AuthorID:   authorID,
+		Text:       body,
+	}
+	verdict, err := s.filter.Check(ctx, content)
+	return content, verdict, err
+}
+
+// holdComment hides a comment the content filter held and records the verdict for moderator review
+func holdComment(ctx context.Context, q *sqlc.Queries, comment sqlc.Comment, content contentfilter.Content, verdict contentfilter.Verdict) (sqlc.Comment, error) {
+	if verdict.Action != contentfilter.ActionHold {
+		return comment, nil
+	}
+	comment, err := q.SetCommentHidden(ctx, sqlc.SetCommentHiddenParams{
+		ID:       comment.ID,
+		IsHidden: true,
+	})
+	if err != nil {
+		return sqlc.Comment{}, fmt.Errorf("failed to hold comment: %s", err.Error())
+	}
+	return comment, contentfilter.Record(ctx, q, content, comment.ID, verdict)
+}
@@ -2,22 +2,34 @@ package comments
 
 import (
 	"context"
+	"errors"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 	"github.com/neevan0842/BlogSphere/backend/internal/common"
 )
 
 type Service interface {
-	CreateComment(ctx context.Context, postID string, userID string, body string) (common.CommentDTO, error)
-	DeleteComment(ctx context.Context, commentID string) error
+	CreateComment(ctx context.Context, postID string, userID string, body string, parentID string) (common.CommentDTO, error)
+	DeleteComment(ctx context.Context, commentID string, user sqlc.User) error
+	SetCommentHidden(ctx context.Context, commentID string, moderatorID pgtype.UUID, hidden bool, reason string) (common.CommentDTO, error)
 	UpdateComment(ctx context.Context, commentID pgtype.UUID, body string) (common.CommentDTO, error)
+	toggleCommentLike(ctx context.Context, commentID pgtype.UUID, userID pgtype.UUID) (bool, error)
 }
 
+var (
+	ErrPostNotFound         = errors.New("post not found")
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrNotCommentAuthor     = errors.New("unauthorized: user does not own the comment")
+	ErrInvalidParentComment = errors.New("parent comment does not exist on this post")
+)
+
 type CreateCommentRequest struct {
-	PostID string `json:"post_id"`
-	Body   string `json:"body"`
+	PostID   string `json:"post_id" validate:"required,uuid"`
+	ParentID string `json:"parent_id" validate:"omitempty,uuid"`
+	Body     string `json:"body" validate:"required,max=5000"`
 }
 
 type UpdateCommentRequest struct {
-	Body string `json:"body"`
+	Body string `json:"body" validate:"required,max=5000"`
 }
@@ -0,0 +1,185 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+	logger  *zap.SugaredLogger
+	repo    *sqlc.Queries
+}
+
+func NewHandler(service Service, logger *zap.SugaredLogger, repo *sqlc.Queries) *handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+		repo:    repo,
+	}
+}
+
+func (h *handler) HandleGetModerationActions(w http.ResponseWriter, r *http.Request) {
+	page, limit, offset := common.GetPaginationParams(r)
+
+	actions, hasMore, err := h.service.getModerationActions(r.Context(), limit, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, ModerationActionsResponse{
+		Actions: actions,
+		Page:    page,
+		Limit:   limit,
+		HasMore: hasMore,
+	})
+}
+
+func (h *handler) HandleUpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	admin, _ := utils.GetUserFromContext(r.Context())
+
+	var payload UpdateRoleRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request payload: %s", err.Error()))
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := h.service.updateUserRole(r.Context(), admin.ID, userID, payload.Role, payload.Reason)
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrCannotChangeOwnRole):
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	case err != nil:
+		h.logger.Errorf("failed to update user role: %s", err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update user role"))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, user)
+}
+
+func (h *handler) HandleSuspendUser(w http.ResponseWriter, r *http.Request) {
+	h.handleSetSuspended(w, r, true)
+}
+
+func (h *handler) HandleUnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	h.handleSetSuspended(w, r, false)
+}
+
+func (h *handler) handleSetSuspended(w http.ResponseWriter, r *http.Request, suspended bool) {
+	moderator, _ := utils.GetUserFromContext(r.Context())
+	userID, err := utils.StrToUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, ErrUserNotFound)
+		return
+	}
+
+	reason, err := common.ReadModerationReason(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := h.service.SetUserSuspended(r.Context(), moderator, userID, suspended, reason)
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrCannotSuspendUser):
+		utils.WriteError(w, http.StatusForbidden, err)
+		return
+	case err != nil:
+		h.logger.Errorf("failed to update user suspension: %s", err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update user suspension"))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, user)
+}
+
+// HandleGetFilterVerdicts lists the content filter's decisions. ?status and ?action filter them; both default to all.
+func (h *handler) HandleGetFilterVerdicts(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	action := r.URL.Query().Get("action")
+	if !common.IsValidFilter(status, contentfilter.StatusPending, contentfilter.StatusApproved, contentfilter.StatusConfirmed) ||
+		!common.IsValidFilter(action, string(contentfilter.ActionHold), string(contentfilter.ActionReject)) {
+		utils.WriteError(w, http.StatusBadRequest, ErrInvalidVerdictFilter)
+		return
+	}
+
+	page, limit, offset := common.GetPaginationParams(r)
+
+	verdicts, hasMore, err := h.service.getFilterVerdicts(r.Context(), status, action, limit, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, FilterVerdictsResponse{
+		Verdicts: verdicts,
+		Page:     page,
+		Limit:    limit,
+		HasMore:  hasMore,
+	})
+}
+
+func (h *handler) HandleGetFilterVerdict(w http.ResponseWriter, r *http.Request) {
+	verdict, err := h.service.getFilterVerdictByID(r.Context(), chi.URLParam(r, "verdictID"))
+	if errors.Is(err, ErrVerdictNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, verdict)
+}
+
+func (h *handler) HandleReviewFilterVerdict(w http.ResponseWriter, r *http.Request) {
+	var payload ReviewFilterVerdictRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request payload: %s", err.Error()))
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	moderator, _ := utils.GetUserFromContext(r.Context())
+
+	verdict, err := h.service.reviewFilterVerdict(r.Context(), moderator, chi.URLParam(r, "verdictID"), payload.Decision, payload.Reason)
+	switch {
+	case errors.Is(err, ErrVerdictNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrVerdictReviewed):
+		utils.WriteError(w, http.StatusConflict, err)
+		return
+	case err != nil:
+		h.logger.Errorf("failed to review content filter verdict: %s", err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to review content filter verdict"))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, verdict)
+}
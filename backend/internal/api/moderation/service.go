@@ -0,0 +1,261 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+type svc struct {
+	repo *sqlc.Queries
+	db   *pgxpool.Pool
+}
+
+func NewService(repo *sqlc.Queries, db *pgxpool.Pool) Service {
+	return &svc{
+		repo: repo,
+		db:   db,
+	}
+}
+
+// getModerationActions returns a page of the moderation log, newest first, and whether there are more
+func (s *svc) getModerationActions(ctx context.Context, limit, offset int) ([]ModerationActionDTO, bool, error) {
+	// fetch one extra row to know if there is another page
+	actions, err := s.repo.GetModerationActions(ctx, sqlc.GetModerationActionsParams{
+		Limit:  int32(limit + 1),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get moderation actions: %s", err.Error())
+	}
+
+	hasMore := len(actions) > limit
+	if hasMore {
+		actions = actions[:limit]
+	}
+
+	result := make([]ModerationActionDTO, len(actions))
+	for i, action := range actions {
+		result[i] = ModerationActionDTO{
+			ID:         action.ID.String(),
+			Action:     action.Action,
+			TargetType: action.TargetType,
+			TargetID:   action.TargetID.String(),
+			Reason:     action.Reason,
+			CreatedAt:  action.CreatedAt.Time,
+		}
+		if action.ModeratorID.Valid {
+			result[i].ModeratorID = action.ModeratorID.String()
+		}
+	}
+	return result, hasMore, nil
+}
+
+// updateUserRole changes a user's role and records the change. Admins cannot change
+// their own role, so there is always someone left who can undo a change.
+func (s *svc) updateUserRole(ctx context.Context, adminID pgtype.UUID, userID string, role string, reason string) (ModeratedUserResponse, error) {
+	userIDUUID, err := utils.StrToUUID(userID)
+	if err != nil {
+		return ModeratedUserResponse{}, ErrUserNotFound
+	}
+	if userIDUUID == adminID {
+		return ModeratedUserResponse{}, ErrCannotChangeOwnRole
+	}
+
+	var user sqlc.User
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		user, err = q.UpdateUserRole(ctx, sqlc.UpdateUserRoleParams{
+			ID:   userIDUUID,
+			Role: role,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update role: %s", err.Error())
+		}
+		return common.RecordModerationAction(ctx, q, adminID, common.ModerationActionChangeRole, common.ModerationTargetUser, user.ID, reason)
+	})
+	if err != nil {
+		return ModeratedUserResponse{}, err
+	}
+
+	return newModeratedUserResponse(user), nil
+}
+
+// SetUserSuspended suspends a user or lifts their suspension, and records the action.
+// Suspending also signs the user out everywhere. Moderators can only act on users with
+// a lower role, so they can't suspend each other or an admin.
+func (s *svc) SetUserSuspended(ctx context.Context, moderator sqlc.User, userID pgtype.UUID, suspended bool, reason string) (ModeratedUserResponse, error) {
+	target, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return ModeratedUserResponse{}, ErrUserNotFound
+	}
+	if utils.HasRole(target.Role, moderator.Role) {
+		return ModeratedUserResponse{}, ErrCannotSuspendUser
+	}
+
+	var user sqlc.User
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		user, err = common.SetUserSuspended(ctx, q, moderator.ID, target.ID, suspended, reason)
+		return err
+	})
+	if err != nil {
+		return ModeratedUserResponse{}, err
+	}
+
+	return newModeratedUserResponse(user), nil
+}
+
+// getFilterVerdicts returns a page of content filter verdicts, oldest first, optionally filtered by status and action
+func (s *svc) getFilterVerdicts(ctx context.Context, status string, action string, limit, offset int) ([]FilterVerdictDTO, bool, error) {
+	// fetch one extra row to know if there is another page
+	verdicts, err := s.repo.GetContentFilterVerdicts(ctx, sqlc.GetContentFilterVerdictsParams{
+		Status: pgtype.Text{String: status, Valid: status != ""},
+		Action: pgtype.Text{String: action, Valid: action != ""},
+		Limit:  int32(limit + 1),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get content filter verdicts: %s", err.Error())
+	}
+
+	hasMore := len(verdicts) > limit
+	if hasMore {
+		verdicts = verdicts[:limit]
+	}
+
+	result := make([]FilterVerdictDTO, len(verdicts))
+	for i, verdict := range verdicts {
+		result[i] = newFilterVerdictDTO(verdict)
+	}
+	return result, hasMore, nil
+}
+
+func (s *svc) getFilterVerdictByID(ctx context.Context, verdictID string) (FilterVerdictDTO, error) {
+	verdict, err := s.getFilterVerdict(ctx, verdictID)
+	if err != nil {
+		return FilterVerdictDTO{}, err
+	}
+	return newFilterVerdictDTO(verdict), nil
+}
+
+// reviewFilterVerdict records a moderator's decision on a pending verdict. Approving held
+// content unhides it in the same transaction and logs it like any other unhide; approving a
+// rejection only marks the filter as wrong, since that content was never saved.
+func (s *svc) reviewFilterVerdict(ctx context.Context, moderator sqlc.User, verdictID string, decision string, reason string) (FilterVerdictDTO, error) {
+	verdict, err := s.getFilterVerdict(ctx, verdictID)
+	if err != nil {
+		return FilterVerdictDTO{}, err
+	}
+	if verdict.Status != contentfilter.StatusPending {
+		return FilterVerdictDTO{}, ErrVerdictReviewed
+	}
+
+	var reviewed sqlc.ContentFilterVerdict
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		status := contentfilter.StatusConfirmed
+		if decision == ReviewApprove {
+			status = contentfilter.StatusApproved
+			if verdict.Action == string(contentfilter.ActionHold) && verdict.TargetID.Valid {
+				if err := unhideTarget(ctx, q, moderator, verdict, reason); err != nil {
+					return err
+				}
+			}
+		}
+
+		reviewed, err = q.ReviewContentFilterVerdict(ctx, sqlc.ReviewContentFilterVerdictParams{
+			ID:         verdict.ID,
+			Status:     status,
+			ReviewedBy: moderator.ID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVerdictReviewed
+		}
+		if err != nil {
+			return fmt.Errorf("failed to review content filter verdict: %s", err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return FilterVerdictDTO{}, err
+	}
+	return newFilterVerdictDTO(reviewed), nil
+}
+
+// unhideTarget shows a held post or comment again. Content purged since it was held is left alone.
+func unhideTarget(ctx context.Context, q *sqlc.Queries, moderator sqlc.User, verdict sqlc.ContentFilterVerdict, reason string) error {
+	var err error
+	switch verdict.TargetType {
+	case common.ModerationTargetPost:
+		_, err = common.SetPostHidden(ctx, q, moderator.ID, verdict.TargetID, false, reason)
+	case common.ModerationTargetComment:
+		_, err = common.SetCommentHidden(ctx, q, moderator.ID, verdict.TargetID, false, reason)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+func (s *svc) getFilterVerdict(ctx context.Context, verdictID string) (sqlc.ContentFilterVerdict, error) {
+	verdictIDUUID, err := utils.StrToUUID(verdictID)
+	if err != nil {
+		return sqlc.ContentFilterVerdict{}, ErrVerdictNotFound
+	}
+	verdict, err := s.repo.GetContentFilterVerdictByID(ctx, verdictIDUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.ContentFilterVerdict{}, ErrVerdictNotFound
+	}
+	if err != nil {
+		return sqlc.ContentFilterVerdict{}, fmt.Errorf("failed to get content filter verdict: %s", err.Error())
+	}
+	return verdict, nil
+}
+
+func newFilterVerdictDTO(verdict sqlc.ContentFilterVerdict) FilterVerdictDTO {
+	dto := FilterVerdictDTO{
+		ID:         verdict.ID.String(),
+		AuthorID:   verdict.AuthorID.String(),
+		TargetType: verdict.TargetType,
+		Action:     verdict.Action,
+		Reasons:    verdict.Reasons,
+		Body:       verdict.Body,
+		Status:     verdict.Status,
+		CreatedAt:  verdict.CreatedAt.Time,
+	}
+	if dto.Reasons == nil {
+		dto.Reasons = []string{}
+	}
+	if verdict.TargetID.Valid {
+		dto.TargetID = verdict.TargetID.String()
+	}
+	if verdict.ReviewedBy.Valid {
+		dto.ReviewedBy = verdict.ReviewedBy.String()
+	}
+	if verdict.ReviewedAt.Valid {
+		reviewedAt := verdict.ReviewedAt.Time
+		dto.ReviewedAt = &reviewedAt
+	}
+	return dto
+}
+
+func newModeratedUserResponse(user sqlc.User) ModeratedUserResponse {
+	response := ModeratedUserResponse{
+		PublicUserDTO: common.NewPublicUserDTO(user),
+		Role:          user.Role,
+	}
+	if user.SuspendedAt.Valid {
+		suspendedAt := user.SuspendedAt.Time
+		response.SuspendedAt = &suspendedAt
+	}
+	return response
+}
@@ -0,0 +1,93 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+)
+
+type Service interface {
+	getModerationActions(ctx context.Context, limit, offset int) ([]ModerationActionDTO, bool, error)
+	updateUserRole(ctx context.Context, adminID pgtype.UUID, userID string, role string, reason string) (ModeratedUserResponse, error)
+	SetUserSuspended(ctx context.Context, moderator sqlc.User, userID pgtype.UUID, suspended bool, reason string) (ModeratedUserResponse, error)
+	getFilterVerdicts(ctx context.Context, status string, action string, limit, offset int) ([]FilterVerdictDTO, bool, error)
+	getFilterVerdictByID(ctx context.Context, verdictID string) (FilterVerdictDTO, error)
+	reviewFilterVerdict(ctx context.Context, moderator sqlc.User, verdictID string, decision string, reason string) (FilterVerdictDTO, error)
+}
+
+var (
+	ErrUserNotFound         = errors.New("user not found")
+	ErrCannotChangeOwnRole  = errors.New("you cannot change your own role")
+	ErrCannotSuspendUser    = errors.New("you can only suspend users with a lower role than yours")
+	ErrVerdictNotFound      = errors.New("content filter verdict not found")
+	ErrVerdictReviewed      = errors.New("content filter verdict has already been reviewed")
+	ErrInvalidVerdictFilter = errors.New("invalid status or action filter")
+)
+
+// Decisions a moderator can review a content filter verdict with
+const (
+	ReviewApprove = "approve" // the filter was wrong; held content is shown again
+	ReviewConfirm = "confirm" // the filter was right; held content stays hidden
+)
+
+// ModerationActionDTO is an entry in the moderation log. ModeratorID is empty once the moderator's account is deleted.
+type ModerationActionDTO struct {
+	ID          string    `json:"id"`
+	ModeratorID string    `json:"moderator_id"`
+	Action      string    `json:"action"`
+	TargetType  string    `json:"target_type"`
+	TargetID    string    `json:"target_id"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ModerationActionsResponse struct {
+	Actions []ModerationActionDTO `json:"actions"`
+	Page    int                   `json:"page"`
+	Limit   int                   `json:"limit"`
+	HasMore bool                  `json:"hasMore"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user moderator admin"`
+	common.ModerationRequest
+}
+
+// ModeratedUserResponse is a user with the account status moderators manage
+type ModeratedUserResponse struct {
+	common.PublicUserDTO
+	Role        string     `json:"role"`
+	SuspendedAt *time.Time `json:"suspended_at"`
+}
+
+type ReviewFilterVerdictRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=approve confirm"`
+	common.ModerationRequest
+}
+
+// FilterVerdictDTO is a post or comment the content filter held or rejected. TargetID is empty
+// for rejected content, which was never saved, and Body is the content as it was submitted.
+type FilterVerdictDTO struct {
+	ID         string     `json:"id"`
+	AuthorID   string     `json:"author_id"`
+	TargetType string     `json:"target_type"`
+	TargetID   string     `json:"target_id,omitempty"`
+	Action     string     `json:"action"`
+	Reasons    []string   `json:"reasons"`
+	Body       string     `json:"body"`
+	Status     string     `json:"status"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type FilterVerdictsResponse struct {
+	Verdicts []FilterVerdictDTO `json:"verdicts"`
+	Page     int                `json:"page"`
+	Limit    int                `json:"limit"`
+	HasMore  bool               `json:"hasMore"`
+}
@@ -1,14 +1,18 @@
 package posts
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"path"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
 	"github.com/neevan0842/BlogSphere/backend/utils"
 	"go.uber.org/zap"
 )
@@ -28,41 +32,94 @@ func NewHandler(service Service, logger *zap.SugaredLogger, repo *sqlc.Queries)
 }
 
 func (h *handler) HandleGetPosts(w http.ResponseWriter, r *http.Request) {
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
 	searchStr := r.URL.Query().Get("search")
 	categoryStr := r.URL.Query().Get("category")
+	sortStr := r.URL.Query().Get("sort")
+	page, limit, offset := common.GetPaginationParams(r)
+
+	// passing a cursor (empty for the first page) switches from page to keyset pagination
+	var cursor *common.Cursor
+	if r.URL.Query().Has("cursor") {
+		cursorPublishedAt, cursorID, err := common.DecodeCursor(r.URL.Query().Get("cursor"))
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		cursor = &common.Cursor{PublishedAt: cursorPublishedAt, ID: cursorID}
+	}
 
-	page, _ := strconv.Atoi(pageStr)
-	if page < 1 {
-		page = 1
+	// searches are ordered by relevance unless recent posts are requested;
+	// keyset pagination follows (published_at, id) so it is always recent-first
+	if sortStr == "" {
+		sortStr = SortRecent
+		if searchStr != "" && cursor == nil {
+			sortStr = SortRelevance
+		}
 	}
-	limit, _ := strconv.Atoi(limitStr)
-	if limit <= 0 || limit > 100 {
-		limit = 20
+	if sortStr != SortRelevance && sortStr != SortRecent {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid sort: must be %q or %q", SortRelevance, SortRecent))
+		return
+	}
+	if cursor != nil && sortStr == SortRelevance {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("cursor pagination only supports sort %q", SortRecent))
+		return
 	}
-	offset := (page - 1) * limit
 
 	// Get requesting user ID (if authenticated)
 	requestingUserID := h.getRequestingUserID(w, r)
 
 	// Fetch posts with pagination, search, and category filter
-	posts, err := h.service.getPostsPaginated(r.Context(), searchStr, categoryStr, limit, offset, requestingUserID)
+	posts, highlights, nextCursor, err := h.service.getPostsPaginated(r.Context(), searchStr, categoryStr, sortStr, limit, offset, cursor, requestingUserID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch posts: %s", err.Error()))
 		return
 	}
 
-	hasMore := len(posts) == limit
 	result := PaginatedResponse{
-		Posts:   posts,
-		Page:    page,
-		Limit:   limit,
-		HasMore: hasMore,
+		Posts:      posts,
+		Page:       page,
+		Limit:      limit,
+		HasMore:    len(posts) == limit,
+		Highlights: highlights,
+	}
+	if cursor != nil {
+		result.Page = 0
+		result.HasMore = nextCursor != ""
+		result.NextCursor = nextCursor
 	}
 	utils.WriteJSON(w, http.StatusOK, result)
 }
 
+func (h *handler) HandleGetFeed(w http.ResponseWriter, r *http.Request) {
+	_, limit, _ := common.GetPaginationParams(r)
+
+	// Get authenticated user ID from context
+	userID, _ := utils.GetUserIDFromContext(r.Context())
+	userIDUUID, err := utils.StrToUUID(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID in token: %s", err.Error()))
+		return
+	}
+
+	cursorPublishedAt, cursorID, err := common.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	posts, nextCursor, err := h.service.getFeedPosts(r.Context(), userIDUUID, cursorPublishedAt, cursorID, limit)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch feed: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, common.CursorPaginatedPosts{
+		Posts:      posts,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	})
+}
+
 // getRequestingUserID extracts and validates the user ID from the request token
 func (h *handler) getRequestingUserID(w http.ResponseWriter, r *http.Request) *pgtype.UUID {
 	return common.GetRequestingUserID(r.Context(), w, r, h.repo)
@@ -74,6 +131,12 @@ func (h *handler) HandleGetPostsBySlug(w http.ResponseWriter, r *http.Request) {
 
 	post, err := h.service.getPostBySlug(r.Context(), slug, requestingUserID)
 	if err != nil {
+		// an old slug redirects to the post's current one
+		if currentSlug, slugErr := h.service.getCurrentSlug(r.Context(), slug); slugErr == nil {
+			w.Header().Set("Location", path.Join(path.Dir(r.URL.Path), currentSlug))
+			utils.WriteJSON(w, http.StatusMovedPermanently, map[string]string{"slug": currentSlug})
+			return
+		}
 		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("failed to fetch post: %s", err.Error()))
 		return
 	}
@@ -82,13 +145,35 @@ func (h *handler) HandleGetPostsBySlug(w http.ResponseWriter, r *http.Request) {
 
 func (h *handler) HandleGetCommentsByPostSlug(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
+	requestingUserID := h.getRequestingUserID(w, r)
+
+	page, limit, offset := common.GetPaginationParams(r)
+
+	sortStr := r.URL.Query().Get("sort")
+	if sortStr == "" {
+		sortStr = CommentSortNewest
+	}
+	if sortStr != CommentSortNewest && sortStr != CommentSortOldest && sortStr != CommentSortMostLiked {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid sort: must be %q, %q or %q", CommentSortNewest, CommentSortOldest, CommentSortMostLiked))
+		return
+	}
 
-	comments, err := h.service.getCommentsByPostSlug(r.Context(), slug)
+	comments, total, err := h.service.getCommentsByPostSlug(r.Context(), slug, sortStr, limit, offset, requestingUserID)
+	if errors.Is(err, ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch comments: %s", err.Error()))
 		return
 	}
-	utils.WriteJSON(w, http.StatusOK, comments)
+	utils.WriteJSON(w, http.StatusOK, PaginatedCommentsResponse{
+		Comments: comments,
+		Page:     page,
+		Limit:    limit,
+		Total:    total,
+		HasMore:  int64(offset+len(comments)) < total,
+	})
 }
 
 // toggle like/unlike a post
@@ -100,6 +185,14 @@ func (h *handler) HandlePostLikes(w http.ResponseWriter, r *http.Request) {
 	userIDUUID, _ := utils.StrToUUID(userID)
 
 	user_has_liked, err := h.service.togglePostLike(r.Context(), postIDUUID, userIDUUID)
+	if errors.Is(err, ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, common.ErrBlocked) {
+		utils.WriteError(w, http.StatusForbidden, err)
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to toggle post like: %s", err.Error()))
 		return
@@ -121,10 +214,27 @@ func (h *handler) HandleCreatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Scheduled posts must go live in the future
+	if payload.PublishAt != nil && !payload.PublishAt.After(time.Now()) {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("validation error: publish_at must be in the future"))
+		return
+	}
+
 	// Get authenticated user ID from context
 	authenticatedUserID, _ := utils.GetUserIDFromContext(r.Context())
 
-	post, err := h.service.CreatePost(r.Context(), payload.Title, payload.Body, authenticatedUserID, payload.CategoryIDs)
+	// posts are published immediately unless saved as a draft or scheduled
+	isPublished := (payload.IsDraft == nil || !*payload.IsDraft) && payload.PublishAt == nil
+
+	post, err := h.service.CreatePost(r.Context(), payload.Title, payload.Body, payload.Slug, authenticatedUserID, payload.CategoryIDs, isPublished, payload.PublishAt)
+	if errors.Is(err, ErrSlugTaken) {
+		utils.WriteError(w, http.StatusConflict, err)
+		return
+	}
+	if errors.Is(err, contentfilter.ErrRejected) {
+		utils.WriteError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to create post: %s", err.Error()))
 		return
@@ -135,11 +245,17 @@ func (h *handler) HandleCreatePost(w http.ResponseWriter, r *http.Request) {
 
 func (h *handler) HandleDeletePost(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "postID")
-	userID, _ := utils.GetUserIDFromContext(r.Context())
+	user, _ := utils.GetUserFromContext(r.Context())
 
-	err := h.service.DeletePost(r.Context(), postID, userID)
-
-	if err != nil {
+	err := h.service.DeletePost(r.Context(), postID, user)
+	switch {
+	case errors.Is(err, ErrPostNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrNotPostAuthor):
+		utils.PermissionDenied(w)
+		return
+	case err != nil:
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to delete post: %s", err.Error()))
 		return
 	}
@@ -147,6 +263,28 @@ func (h *handler) HandleDeletePost(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// HandleRestorePost undoes the deletion of one of the user's posts within the restore window
+func (h *handler) HandleRestorePost(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postID")
+	user, _ := utils.GetUserFromContext(r.Context())
+
+	post, err := h.service.restorePost(r.Context(), postID, user.ID)
+	if errors.Is(err, ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, ErrDeletedByModerator) {
+		utils.WriteError(w, http.StatusForbidden, err)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, post)
+}
+
 func (h *handler) HandleUpdatePost(w http.ResponseWriter, r *http.Request) {
 	postID := chi.URLParam(r, "postID")
 	userID, _ := utils.GetUserIDFromContext(r.Context())
@@ -163,7 +301,29 @@ func (h *handler) HandleUpdatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newPost, err := h.service.UpdatePost(r.Context(), postID, payload.Title, payload.Body, payload.CategoryIDs, userID)
+	// Scheduled posts must go live in the future
+	if payload.PublishAt != nil && !payload.PublishAt.After(time.Now()) {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("validation error: publish_at must be in the future"))
+		return
+	}
+
+	newPost, err := h.service.UpdatePost(r.Context(), postID, payload.Title, payload.Body, payload.Slug, payload.CategoryIDs, userID, payload.IsDraft, payload.PublishAt)
+	if errors.Is(err, ErrPostNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, ErrNotPostAuthor) {
+		utils.PermissionDenied(w)
+		return
+	}
+	if errors.Is(err, ErrSlugTaken) {
+		utils.WriteError(w, http.StatusConflict, err)
+		return
+	}
+	if errors.Is(err, contentfilter.ErrRejected) {
+		utils.WriteError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update post: %s", err.Error()))
 		return
@@ -184,3 +344,137 @@ func (h *handler) HandleGetPostByID(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJSON(w, http.StatusOK, post)
 }
+
+func (h *handler) HandlePublishPost(w http.ResponseWriter, r *http.Request) {
+	h.handleSetPublished(w, r, true)
+}
+
+func (h *handler) HandleUnpublishPost(w http.ResponseWriter, r *http.Request) {
+	h.handleSetPublished(w, r, false)
+}
+
+// handleSetPublished publishes or unpublishes a post owned by the authenticated user
+func (h *handler) handleSetPublished(w http.ResponseWriter, r *http.Request, published bool) {
+	postID := chi.URLParam(r, "postID")
+	userID, _ := utils.GetUserIDFromContext(r.Context())
+
+	post, err := h.service.setPostPublished(r.Context(), postID, userID, published)
+	switch {
+	case errors.Is(err, ErrPostNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrNotPostAuthor):
+		utils.PermissionDenied(w)
+		return
+	case err != nil:
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update post status: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, post)
+}
+
+func (h *handler) HandleHidePost(w http.ResponseWriter, r *http.Request) {
+	h.handleSetHidden(w, r, true)
+}
+
+func (h *handler) HandleUnhidePost(w http.ResponseWriter, r *http.Request) {
+	h.handleSetHidden(w, r, false)
+}
+
+// handleSetHidden hides or unhides a post. Only moderators are routed here.
+func (h *handler) handleSetHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	postID := chi.URLParam(r, "postID")
+	moderator, _ := utils.GetUserFromContext(r.Context())
+
+	reason, err := common.ReadModerationReason(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	post, err := h.service.SetPostHidden(r.Context(), postID, moderator.ID, hidden, reason)
+	switch {
+	case errors.Is(err, ErrPostNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case err != nil:
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update post visibility: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, post)
+}
+
+func (h *handler) HandleGetPostRevisions(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postID")
+	userID, _ := utils.GetUserIDFromContext(r.Context())
+
+	revisions, err := h.service.getPostRevisions(r.Context(), postID, userID)
+	if h.writeRevisionError(w, err) {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, revisions)
+}
+
+func (h *handler) HandleGetPostRevision(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postID")
+	userID, _ := utils.GetUserIDFromContext(r.Context())
+
+	n, err := parseRevisionNumber(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	revision, err := h.service.getPostRevision(r.Context(), postID, userID, n)
+	if h.writeRevisionError(w, err) {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, revision)
+}
+
+func (h *handler) HandleRestorePostRevision(w http.ResponseWriter, r *http.Request) {
+	postID := chi.URLParam(r, "postID")
+	userID, _ := utils.GetUserIDFromContext(r.Context())
+
+	n, err := parseRevisionNumber(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	post, err := h.service.restorePostRevision(r.Context(), postID, userID, n)
+	if h.writeRevisionError(w, err) {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, post)
+}
+
+// writeRevisionError maps revision service errors to responses and reports whether one was written
+func (h *handler) writeRevisionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, ErrPostNotFound), errors.Is(err, ErrRevisionNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrNotPostAuthor):
+		utils.PermissionDenied(w)
+	case errors.Is(err, contentfilter.ErrRejected):
+		utils.WriteError(w, http.StatusUnprocessableEntity, err)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to process revision: %s", err.Error()))
+	}
+	return true
+}
+
+func parseRevisionNumber(r *http.Request) (int32, error) {
+	n, err := strconv.ParseInt(chi.URLParam(r, "n"), 10, 32)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid revision number")
+	}
+	return int32(n), nil
+}
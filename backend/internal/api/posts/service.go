@@ -4,41 +4,108 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
 	"github.com/neevan0842/BlogSphere/backend/utils"
 )
 
 type svc struct {
-	repo *sqlc.Queries
-	db   *pgxpool.Pool
+	repo   *sqlc.Queries
+	db     *pgxpool.Pool
+	filter *contentfilter.Pipeline
 }
 
-func NewService(repo *sqlc.Queries, db *pgxpool.Pool) Service {
+// NewService creates the posts service. New and edited posts go through the content
+// filter, which can reject them or hide them until a moderator approves them.
+func NewService(repo *sqlc.Queries, db *pgxpool.Pool, filter *contentfilter.Pipeline) Service {
 	return &svc{
-		repo: repo,
-		db:   db,
+		repo:   repo,
+		db:     db,
+		filter: filter,
 	}
 }
 
-func (s *svc) getPostsPaginated(ctx context.Context, search string, categorySlug string, limit, offset int, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, error) {
-	// Fetch posts based on search query with pagination
-	posts, err := s.repo.GetPostBySearchAndCategoryPaginated(ctx, sqlc.GetPostBySearchAndCategoryPaginatedParams{
+// getPostsPaginated lists published posts either by page (offset) or, when a cursor is
+// given, by keyset after that cursor. The next cursor is only returned in cursor mode.
+func (s *svc) getPostsPaginated(ctx context.Context, search string, categorySlug string, sort string, limit, offset int, cursor *common.Cursor, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, map[string]string, string, error) {
+	params := sqlc.GetPostBySearchAndCategoryPaginatedParams{
 		CategorySlug: pgtype.Text{String: categorySlug, Valid: categorySlug != ""},
 		Search:       pgtype.Text{String: search, Valid: search != ""},
+		Sort:         sort,
 		Limit:        int32(limit),
 		Offset:       int32(offset),
-	})
+	}
+	if requestingUserID != nil {
+		// leaves out posts by authors the user has muted
+		params.ViewerID = *requestingUserID
+	}
+	if cursor != nil {
+		// fetch one extra row to know whether another page exists
+		params.CursorPublishedAt = cursor.PublishedAt
+		params.CursorID = cursor.ID
+		params.Limit = int32(limit + 1)
+		params.Offset = 0
+	}
+
+	// Fetch posts based on search query with pagination
+	rows, err := s.repo.GetPostBySearchAndCategoryPaginated(ctx, params)
 	if err != nil {
-		return []common.PostCardDTO{}, fmt.Errorf("failed to fetch posts: %s", err.Error())
+		return []common.PostCardDTO{}, nil, "", fmt.Errorf("failed to fetch posts: %s", err.Error())
+	}
+
+	// collect posts and their highlighted search snippets
+	posts := make([]sqlc.Post, len(rows))
+	var highlights map[string]string
+	if search != "" {
+		highlights = make(map[string]string, len(rows))
+	}
+	for i, row := range rows {
+		posts[i] = row.Post
+		if highlights != nil && row.Snippet != "" {
+			highlights[row.Post.ID.String()] = row.Snippet
+		}
+	}
+
+	nextCursor := ""
+	if cursor != nil {
+		posts, nextCursor = common.TrimPostsPage(posts, limit)
 	}
 
 	// Enrich posts with additional details
-	return common.EnrichPostsWithDetails(ctx, s.repo, posts, requestingUserID)
+	enriched, err := common.EnrichPostsWithDetails(ctx, s.repo, posts, requestingUserID)
+	if err != nil {
+		return []common.PostCardDTO{}, nil, "", err
+	}
+	return enriched, highlights, nextCursor, nil
+}
+
+// getFeedPosts returns published posts from authors the user follows and hasn't muted, newest
+// first, along with the cursor for the next page (empty when there are no more posts)
+func (s *svc) getFeedPosts(ctx context.Context, userID pgtype.UUID, cursorPublishedAt pgtype.Timestamptz, cursorID pgtype.UUID, limit int) ([]common.PostCardDTO, string, error) {
+	// fetch one extra row to know whether another page exists
+	posts, err := s.repo.GetFeedPostsByUserID(ctx, sqlc.GetFeedPostsByUserIDParams{
+		UserID:            userID,
+		CursorPublishedAt: cursorPublishedAt,
+		CursorID:          cursorID,
+		Limit:             int32(limit + 1),
+	})
+	if err != nil {
+		return []common.PostCardDTO{}, "", fmt.Errorf("failed to fetch feed posts: %s", err.Error())
+	}
+
+	posts, nextCursor := common.TrimPostsPage(posts, limit)
+
+	enriched, err := common.EnrichPostsWithDetails(ctx, s.repo, posts, &userID)
+	if err != nil {
+		return []common.PostCardDTO{}, "", err
+	}
+	return enriched, nextCursor, nil
 }
 
 func (s *svc) getUserByID(ctx context.Context, userID pgtype.UUID) (sqlc.User, error) {
@@ -55,6 +122,10 @@ func (s *svc) getPostBySlug(ctx context.Context, slug string, requestingUserID *
 		return common.PostCardDTO{}, fmt.Errorf("failed to get post by slug: %s", err.Error())
 	}
 
+	if !common.CanViewPost(post, requestingUserID) {
+		return common.PostCardDTO{}, ErrPostNotFound
+	}
+
 	posts, err := common.EnrichPostsWithDetails(ctx, s.repo, []sqlc.Post{post}, requestingUserID)
 	if err != nil {
 		return common.PostCardDTO{}, fmt.Errorf("failed to enrich post details: %s", err.Error())
@@ -67,6 +138,15 @@ func (s *svc) getPostBySlug(ctx context.Context, slug string, requestingUserID *
 	return posts[0], nil
 }
 
+// getCurrentSlug returns the slug now used by the post that used to be at oldSlug
+func (s *svc) getCurrentSlug(ctx context.Context, oldSlug string) (string, error) {
+	slug, err := s.repo.GetCurrentSlugByOldSlug(ctx, oldSlug)
+	if err != nil {
+		return "", ErrPostNotFound
+	}
+	return slug, nil
+}
+
 func (s *svc) getPostByID(ctx context.Context, postID string, requestingUserID *pgtype.UUID) (common.PostCardDTO, error) {
 	postIDUUID, err := utils.StrToUUID(postID)
 	if err != nil {
@@ -78,6 +158,10 @@ func (s *svc) getPostByID(ctx context.Context, postID string, requestingUserID *
 		return common.PostCardDTO{}, fmt.Errorf("failed to get post by ID: %s", err.Error())
 	}
 
+	if !common.CanViewPost(post, requestingUserID) {
+		return common.PostCardDTO{}, ErrPostNotFound
+	}
+
 	posts, err := common.EnrichPostsWithDetails(ctx, s.repo, []sqlc.Post{post}, requestingUserID)
 	if err != nil {
 		return common.PostCardDTO{}, fmt.Errorf("failed to enrich post details: %s", err.Error())
@@ -90,18 +174,78 @@ func (s *svc) getPostByID(ctx context.Context, postID string, requestingUserID *
 	return posts[0], nil
 }
 
-func (s *svc) getCommentsByPostSlug(ctx context.Context, slug string) ([]common.CommentDTO, error) {
-	comments, err := s.repo.GetCommentsByPostSlug(ctx, slug)
+// getCommentsByPostSlug returns a page of top-level comments with their reply threads,
+// along with the total number of top-level comments on the post
+func (s *svc) getCommentsByPostSlug(ctx context.Context, slug string, sort string, limit, offset int, requestingUserID *pgtype.UUID) ([]common.CommentDTO, int64, error) {
+	post, err := s.repo.GetPostBySlug(ctx, slug)
+	if err != nil {
+		// links to a post's old slug still show its comments
+		if slug, err = s.getCurrentSlug(ctx, slug); err != nil {
+			return nil, 0, ErrPostNotFound
+		}
+		post, err = s.repo.GetPostBySlug(ctx, slug)
+	}
+	if err != nil || !common.CanViewPost(post, requestingUserID) {
+		return nil, 0, ErrPostNotFound
+	}
+
+	// comments by users the viewer has muted are left out
+	var viewer pgtype.UUID
+	if requestingUserID != nil {
+		viewer = *requestingUserID
+	}
+	roots, err := s.repo.GetCommentsByPostSlug(ctx, sqlc.GetCommentsByPostSlugParams{
+		Slug:     slug,
+		ViewerID: viewer,
+		Sort:     sort,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get comments by post slug: %s", err.Error())
+	}
+
+	total, err := s.repo.CountCommentsByPostSlug(ctx, sqlc.CountCommentsByPostSlugParams{
+		Slug:     slug,
+		ViewerID: viewer,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count comments: %s", err.Error())
+	}
+
+	comments := roots
+	if len(roots) > 0 {
+		rootIDs := make([]pgtype.UUID, len(roots))
+		for i, root := range roots {
+			rootIDs[i] = root.ID
+		}
+		replies, err := s.repo.GetRepliesByRootCommentIDs(ctx, sqlc.GetRepliesByRootCommentIDsParams{
+			RootIds:  rootIDs,
+			ViewerID: viewer,
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get comment replies: %s", err.Error())
+		}
+		comments = append(comments, replies...)
+	}
+
+	enriched, err := common.EnrichCommentsWithAuthors(ctx, s.repo, comments, requestingUserID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get comments by post slug: %s", err.Error())
+		return nil, 0, err
 	}
 
-	return common.EnrichCommentsWithAuthors(ctx, s.repo, comments)
+	return common.BuildCommentTree(enriched), total, nil
 }
 
 func (s *svc) togglePostLike(ctx context.Context, postID pgtype.UUID, userID pgtype.UUID) (bool, error) {
+	// Only posts visible to the user can be liked
+	post, err := s.repo.GetPostByID(ctx, postID)
+	if err != nil || !common.CanViewPost(post, &userID) {
+		return false, ErrPostNotFound
+	}
+
 	// Check if user has already liked the post
-	_, err := s.repo.GetPostLike(ctx, sqlc.GetPostLikeParams{
+	_, err = s.repo.GetPostLike(ctx, sqlc.GetPostLikeParams{
 		PostID: postID,
 		UserID: userID,
 	})
@@ -111,7 +255,10 @@ func (s *svc) togglePostLike(ctx context.Context, postID pgtype.UUID, userID pgt
 	}
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		// User has not liked the post, so add like
+		// User has not liked the post, so add like unless its author has blocked them
+		if err := common.CheckNotBlocked(ctx, s.repo, post.AuthorID, userID); err != nil {
+			return false, err
+		}
 		_, err := s.repo.CreatePostLike(ctx, sqlc.CreatePostLikeParams{
 			PostID: postID,
 			UserID: userID,
@@ -133,14 +280,24 @@ func (s *svc) togglePostLike(ctx context.Context, postID pgtype.UUID, userID pgt
 	}
 }
 
-func (s *svc) CreatePost(ctx context.Context, title string, body string, authorID string, categoryIDs []string) (common.PostCardDTO, error) {
+func (s *svc) CreatePost(ctx context.Context, title string, body string, slug string, authorID string, categoryIDs []string, isPublished bool, publishAt *time.Time) (common.PostCardDTO, error) {
+	authorUUID, err := utils.StrToUUID(authorID)
+	if err != nil {
+		return common.PostCardDTO{}, fmt.Errorf("invalid author ID: %s", err.Error())
+	}
+
+	content, verdict, err := s.checkContent(ctx, pgtype.UUID{}, authorUUID, title, body)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+
 	var createdPost sqlc.Post
-	err := common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
-		// Generate slug from title
-		slug := utils.GenerateSlug(title)
-		authorUUID, err := utils.StrToUUID(authorID)
-		if err != nil {
-			return fmt.Errorf("invalid author ID: %s", err.Error())
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		// Use the author's slug if given, otherwise generate one from the title
+		if slug == "" {
+			slug = utils.GenerateSlug(title)
+		} else if err := checkSlugAvailable(ctx, q, slug, pgtype.UUID{}); err != nil {
+			return err
 		}
 
 		// Create the post
@@ -149,11 +306,20 @@ func (s *svc) CreatePost(ctx context.Context, title string, body string, authorI
 			Body:        body,
 			Slug:        slug,
 			AuthorID:    authorUUID,
-			IsPublished: true,
+			IsPublished: isPublished,
+			PublishAt:   toTimestamptz(publishAt),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create post: %s", err.Error())
 		}
+		if post, err = holdPost(ctx, q, post, content, verdict); err != nil {
+			return err
+		}
+
+		// The initial content is the first revision
+		if err := recordRevision(ctx, q, post); err != nil {
+			return err
+		}
 
 		// Associate post with categories
 		postIDUUIDs := make([]pgtype.UUID, len(categoryIDs))
@@ -187,33 +353,41 @@ func (s *svc) CreatePost(ctx context.Context, title string, body string, authorI
 	return posts[0], nil
 }
 
-func (s *svc) DeletePost(ctx context.Context, postID string, userID string) error {
+// DeletePost deletes a post on behalf of its author or a moderator. Deletions by a
+// moderator are recorded in the moderation log. The post is kept until the restore window
+// passes, and its author can restore it in the meantime unless a moderator deleted it.
+func (s *svc) DeletePost(ctx context.Context, postID string, user sqlc.User) error {
 	postIDUUID, err := utils.StrToUUID(postID)
 	if err != nil {
-		return fmt.Errorf("invalid post ID: %s", err.Error())
+		return ErrPostNotFound
 	}
 
-	// Fetch the post to verify ownership
 	post, err := s.repo.GetPostByID(ctx, postIDUUID)
 	if err != nil {
-		return fmt.Errorf("no post found with the given ID: %s", err.Error())
+		return ErrPostNotFound
 	}
 
-	// Check if the requesting user is the author of the post
-	if post.AuthorID.String() != userID {
-		return fmt.Errorf("unauthorized: user does not own the post")
+	isAuthor := post.AuthorID == user.ID
+	if !isAuthor && !utils.CanModerate(user.Role) {
+		return ErrNotPostAuthor
 	}
 
-	// Delete the post
-	err = s.repo.DeletePost(ctx, postIDUUID)
-	if err != nil {
-		return fmt.Errorf("failed to delete post: %s", err.Error())
-	}
-
-	return nil
+	return common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		err := q.SoftDeletePost(ctx, sqlc.SoftDeletePostParams{
+			ID:        post.ID,
+			DeletedBy: user.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete post: %s", err.Error())
+		}
+		if isAuthor {
+			return nil
+		}
+		return common.RecordModerationAction(ctx, q, user.ID, common.ModerationActionDelete, common.ModerationTargetPost, post.ID, "")
+	})
 }
 
-func (s *svc) UpdatePost(ctx context.Context, postID string, title string, body string, categoryIDs []string, userID string) (common.PostCardDTO, error) {
+func (s *svc) UpdatePost(ctx context.Context, postID string, title string, body string, slug string, categoryIDs []string, userID string, isDraft *bool, publishAt *time.Time) (common.PostCardDTO, error) {
 	postIDUUID, err := utils.StrToUUID(postID)
 	if err != nil {
 		return common.PostCardDTO{}, fmt.Errorf("invalid post ID: %s", err.Error())
@@ -222,30 +396,81 @@ func (s *svc) UpdatePost(ctx context.Context, postID string, title string, body
 	// Fetch the post to verify ownership
 	oldPost, err := s.repo.GetPostByID(ctx, postIDUUID)
 	if err != nil {
-		return common.PostCardDTO{}, fmt.Errorf("no post found with the given ID: %s", err.Error())
+		return common.PostCardDTO{}, ErrPostNotFound
 	}
 
 	// Check if the requesting user is the author of the post
 	if oldPost.AuthorID.String() != userID {
-		return common.PostCardDTO{}, fmt.Errorf("unauthorized: user does not own the post")
+		return common.PostCardDTO{}, ErrNotPostAuthor
+	}
+
+	content, verdict, err := s.checkContent(ctx, postIDUUID, oldPost.AuthorID, title, body)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+
+	// Keep the published status and schedule unchanged unless the author explicitly
+	// schedules the post or asks for a draft or not, which cancels any schedule
+	isPublished := oldPost.IsPublished
+	scheduledAt := oldPost.PublishAt
+	switch {
+	case publishAt != nil:
+		isPublished = false
+		scheduledAt = toTimestamptz(publishAt)
+	case isDraft != nil:
+		isPublished = !*isDraft
+		scheduledAt = pgtype.Timestamptz{}
 	}
 
 	var updatedPost sqlc.Post
 	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
-		// Generate slug from title
-		slug := utils.GenerateSlug(title)
+		// The slug only changes when the author asks for a new one; the old slug
+		// is kept in the history so existing links still resolve
+		newSlug := oldPost.Slug
+		if slug != "" && slug != oldPost.Slug {
+			if err := checkSlugAvailable(ctx, q, slug, postIDUUID); err != nil {
+				return err
+			}
+			err := q.CreatePostSlugHistory(ctx, sqlc.CreatePostSlugHistoryParams{
+				Slug:   oldPost.Slug,
+				PostID: postIDUUID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save slug history: %s", err.Error())
+			}
+			// switching back to a previous slug takes it out of the history
+			err = q.DeletePostSlugHistory(ctx, sqlc.DeletePostSlugHistoryParams{
+				Slug:   slug,
+				PostID: postIDUUID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update slug history: %s", err.Error())
+			}
+			newSlug = slug
+		}
 
 		// Update the post
 		newPost, err := q.UpdatePost(ctx, sqlc.UpdatePostParams{
 			ID:          postIDUUID,
 			Title:       title,
 			Body:        body,
-			Slug:        slug,
-			IsPublished: oldPost.IsPublished, // Keep the published status unchanged
+			Slug:        newSlug,
+			IsPublished: isPublished,
+			PublishAt:   scheduledAt,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to update post: %s", err.Error())
 		}
+		if newPost, err = holdPost(ctx, q, newPost, content, verdict); err != nil {
+			return err
+		}
+
+		// Only edits to the content are worth a new revision
+		if newPost.Title != oldPost.Title || newPost.Body != oldPost.Body {
+			if err := recordRevision(ctx, q, newPost); err != nil {
+				return err
+			}
+		}
 
 		// delete existing post-category associations
 		if err = q.DeletePostCategoriesByPostID(ctx, postIDUUID); err != nil {
@@ -286,3 +511,278 @@ func (s *svc) UpdatePost(ctx context.Context, postID string, title string, body
 
 	return posts[0], nil
 }
+
+func (s *svc) setPostPublished(ctx context.Context, postID string, userID string, published bool) (common.PostCardDTO, error) {
+	post, err := s.getAuthoredPost(ctx, postID, userID)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+
+	updatedPost, err := s.repo.SetPostPublished(ctx, sqlc.SetPostPublishedParams{
+		ID:          post.ID,
+		IsPublished: published,
+	})
+	if err != nil {
+		return common.PostCardDTO{}, fmt.Errorf("failed to update post status: %s", err.Error())
+	}
+
+	posts, err := common.EnrichPostsWithDetails(ctx, s.repo, []sqlc.Post{updatedPost}, &updatedPost.AuthorID)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+	return posts[0], nil
+}
+
+// restorePost brings back a post its author deleted within the restore window. Posts
+// deleted by a moderator stay deleted.
+func (s *svc) restorePost(ctx context.Context, postID string, userID pgtype.UUID) (common.PostCardDTO, error) {
+	postIDUUID, err := utils.StrToUUID(postID)
+	if err != nil {
+		return common.PostCardDTO{}, ErrPostNotFound
+	}
+
+	deleted, err := s.repo.GetDeletedPostByID(ctx, postIDUUID)
+	if err != nil || deleted.AuthorID != userID {
+		return common.PostCardDTO{}, ErrPostNotFound
+	}
+	if deleted.DeletedBy != deleted.AuthorID {
+		return common.PostCardDTO{}, ErrDeletedByModerator
+	}
+
+	post, err := s.repo.RestorePost(ctx, sqlc.RestorePostParams{
+		ID:           postIDUUID,
+		AuthorID:     userID,
+		DeletedAfter: common.RestoreCutoff(),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return common.PostCardDTO{}, ErrPostNotFound
+	}
+	if err != nil {
+		return common.PostCardDTO{}, fmt.Errorf("failed to restore post: %s", err.Error())
+	}
+
+	posts, err := common.EnrichPostsWithDetails(ctx, s.repo, []sqlc.Post{post}, &userID)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+	return posts[0], nil
+}
+
+// SetPostHidden hides a post from everyone but its author, or makes it visible again, and records the action
+func (s *svc) SetPostHidden(ctx context.Context, postID string, moderatorID pgtype.UUID, hidden bool, reason string) (common.PostCardDTO, error) {
+	postIDUUID, err := utils.StrToUUID(postID)
+	if err != nil {
+		return common.PostCardDTO{}, ErrPostNotFound
+	}
+
+	var updatedPost sqlc.Post
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		updatedPost, err = common.SetPostHidden(ctx, q, moderatorID, postIDUUID, hidden, reason)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPostNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+
+	posts, err := common.EnrichPostsWithDetails(ctx, s.repo, []sqlc.Post{updatedPost}, &moderatorID)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+	return posts[0], nil
+}
+
+// checkContent runs a post's title and body through the content filter. Rejected posts return
+// contentfilter.ErrRejected; held posts are hidden by holdPost when they are saved.
+func (s *svc) checkContent(ctx context.Context, postID pgtype.UUID, authorID pgtype.UUID, title string, body string) (contentfilter.Content, contentfilter.Verdict, error) {
+	content := contentfilter.Content{
+		TargetType: common.ModerationTargetPost,
+		TargetID:   postID,
+		AuthorID:   authorID,
+		Text:       title + "\n\n" + body,
+	}
+	verdict, err := s.filter.Check(ctx, content)
+	return content, verdict, err
+}
+
+// holdPost hides a post the content filter held and records the verdict for moderator review
+func holdPost(ctx context.Context, q *sqlc.Queries, post sqlc.Post, content contentfilter.Content, verdict contentfilter.Verdict) (sqlc.Post, error) {
+	if verdict.Action != contentfilter.ActionHold {
+		return post, nil
+	}
+	post, err := q.SetPostHidden(ctx, sqlc.SetPostHiddenParams{
+		ID:       post.ID,
+		IsHidden: true,
+	})
+	if err != nil {
+		return sqlc.Post{}, fmt.Errorf("failed to hold post: %s", err.Error())
+	}
+	return post, contentfilter.Record(ctx, q, content, post.ID, verdict)
+}
+
+func (s *svc) getPostRevisions(ctx context.Context, postID string, userID string) ([]PostRevisionDTO, error) {
+	post, err := s.getAuthoredPost(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.GetPostRevisionsByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post revisions: %s", err.Error())
+	}
+
+	result := make([]PostRevisionDTO, len(revisions))
+	for i, revision := range revisions {
+		result[i] = toPostRevisionDTO(revision)
+		result[i].Body = "" // the listing only summarises each revision
+	}
+	return result, nil
+}
+
+// getPostRevision returns revision n along with a line diff against revision n-1.
+// The first revision is diffed against an empty post.
+func (s *svc) getPostRevision(ctx context.Context, postID string, userID string, n int32) (PostRevisionResponse, error) {
+	post, err := s.getAuthoredPost(ctx, postID, userID)
+	if err != nil {
+		return PostRevisionResponse{}, err
+	}
+
+	revision, err := s.repo.GetPostRevision(ctx, sqlc.GetPostRevisionParams{
+		PostID:         post.ID,
+		RevisionNumber: n,
+	})
+	if err != nil {
+		return PostRevisionResponse{}, ErrRevisionNotFound
+	}
+
+	var previous sqlc.PostRevision
+	if n > 1 {
+		previous, err = s.repo.GetPostRevision(ctx, sqlc.GetPostRevisionParams{
+			PostID:         post.ID,
+			RevisionNumber: n - 1,
+		})
+		if err != nil {
+			return PostRevisionResponse{}, fmt.Errorf("failed to get previous revision: %s", err.Error())
+		}
+	}
+
+	return PostRevisionResponse{
+		PostRevisionDTO: toPostRevisionDTO(revision),
+		TitleDiff:       utils.DiffLines(previous.Title, revision.Title),
+		BodyDiff:        utils.DiffLines(previous.Body, revision.Body),
+	}, nil
+}
+
+// restorePostRevision copies the title and body of revision n back onto the post.
+// The restore is recorded as a new revision so it can be undone the same way, and it goes
+// through the content filter like any other edit.
+func (s *svc) restorePostRevision(ctx context.Context, postID string, userID string, n int32) (common.PostCardDTO, error) {
+	post, err := s.getAuthoredPost(ctx, postID, userID)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+
+	revision, err := s.repo.GetPostRevision(ctx, sqlc.GetPostRevisionParams{
+		PostID:         post.ID,
+		RevisionNumber: n,
+	})
+	if err != nil {
+		return common.PostCardDTO{}, ErrRevisionNotFound
+	}
+
+	content, verdict, err := s.checkContent(ctx, post.ID, post.AuthorID, revision.Title, revision.Body)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+
+	var restoredPost sqlc.Post
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		restoredPost, err = q.UpdatePostContent(ctx, sqlc.UpdatePostContentParams{
+			ID:    post.ID,
+			Title: revision.Title,
+			Body:  revision.Body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore post: %s", err.Error())
+		}
+		if restoredPost, err = holdPost(ctx, q, restoredPost, content, verdict); err != nil {
+			return err
+		}
+
+		return recordRevision(ctx, q, restoredPost)
+	})
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+
+	posts, err := common.EnrichPostsWithDetails(ctx, s.repo, []sqlc.Post{restoredPost}, &restoredPost.AuthorID)
+	if err != nil {
+		return common.PostCardDTO{}, err
+	}
+	return posts[0], nil
+}
+
+// getAuthoredPost fetches a post and verifies that userID is its author
+func (s *svc) getAuthoredPost(ctx context.Context, postID string, userID string) (sqlc.Post, error) {
+	postIDUUID, err := utils.StrToUUID(postID)
+	if err != nil {
+		return sqlc.Post{}, ErrPostNotFound
+	}
+
+	post, err := s.repo.GetPostByID(ctx, postIDUUID)
+	if err != nil {
+		return sqlc.Post{}, ErrPostNotFound
+	}
+
+	if post.AuthorID.String() != userID {
+		return sqlc.Post{}, ErrNotPostAuthor
+	}
+	return post, nil
+}
+
+// checkSlugAvailable returns ErrSlugTaken if slug belongs, now or previously, to a post other than postID
+func checkSlugAvailable(ctx context.Context, q *sqlc.Queries, slug string, postID pgtype.UUID) error {
+	taken, err := q.IsSlugTaken(ctx, sqlc.IsSlugTakenParams{
+		Slug:   slug,
+		PostID: postID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check slug: %s", err.Error())
+	}
+	if taken {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+// recordRevision snapshots the post's current title and body as its next revision
+func recordRevision(ctx context.Context, q *sqlc.Queries, post sqlc.Post) error {
+	_, err := q.CreatePostRevision(ctx, sqlc.CreatePostRevisionParams{
+		PostID: post.ID,
+		Title:  post.Title,
+		Body:   post.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record post revision: %s", err.Error())
+	}
+	return nil
+}
+
+func toPostRevisionDTO(revision sqlc.PostRevision) PostRevisionDTO {
+	return PostRevisionDTO{
+		RevisionNumber: revision.RevisionNumber,
+		Title:          revision.Title,
+		Body:           revision.Body,
+		CreatedAt:      revision.CreatedAt.Time,
+	}
+}
+
+// toTimestamptz converts an optional time into a nullable timestamp
+func toTimestamptz(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
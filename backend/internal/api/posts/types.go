@@ -2,29 +2,72 @@ package posts
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/utils"
 )
 
 type Service interface {
-	getPostsPaginated(ctx context.Context, search string, categorySlug string, limit, offset int, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, error)
+	getFeedPosts(ctx context.Context, userID pgtype.UUID, cursorPublishedAt pgtype.Timestamptz, cursorID pgtype.UUID, limit int) ([]common.PostCardDTO, string, error)
+	getPostsPaginated(ctx context.Context, search string, categorySlug string, sort string, limit, offset int, cursor *common.Cursor, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, map[string]string, string, error)
 	getUserByID(ctx context.Context, userID pgtype.UUID) (sqlc.User, error)
 	getPostBySlug(ctx context.Context, slug string, requestingUserID *pgtype.UUID) (common.PostCardDTO, error)
+	getCurrentSlug(ctx context.Context, oldSlug string) (string, error)
 	getPostByID(ctx context.Context, postID string, requestingUserID *pgtype.UUID) (common.PostCardDTO, error)
-	getCommentsByPostSlug(ctx context.Context, slug string) ([]common.CommentDTO, error)
+	getCommentsByPostSlug(ctx context.Context, slug string, sort string, limit, offset int, requestingUserID *pgtype.UUID) ([]common.CommentDTO, int64, error)
 	togglePostLike(ctx context.Context, postID pgtype.UUID, userID pgtype.UUID) (bool, error)
-	CreatePost(ctx context.Context, title string, body string, authorID string, categoryIDs []string) (common.PostCardDTO, error)
-	DeletePost(ctx context.Context, postID string, userID string) error
-	UpdatePost(ctx context.Context, postID string, title string, body string, categoryIDs []string, userID string) (common.PostCardDTO, error)
+	CreatePost(ctx context.Context, title string, body string, slug string, authorID string, categoryIDs []string, isPublished bool, publishAt *time.Time) (common.PostCardDTO, error)
+	DeletePost(ctx context.Context, postID string, user sqlc.User) error
+	restorePost(ctx context.Context, postID string, userID pgtype.UUID) (common.PostCardDTO, error)
+	UpdatePost(ctx context.Context, postID string, title string, body string, slug string, categoryIDs []string, userID string, isDraft *bool, publishAt *time.Time) (common.PostCardDTO, error)
+	setPostPublished(ctx context.Context, postID string, userID string, published bool) (common.PostCardDTO, error)
+	SetPostHidden(ctx context.Context, postID string, moderatorID pgtype.UUID, hidden bool, reason string) (common.PostCardDTO, error)
+	getPostRevisions(ctx context.Context, postID string, userID string) ([]PostRevisionDTO, error)
+	getPostRevision(ctx context.Context, postID string, userID string, n int32) (PostRevisionResponse, error)
+	restorePostRevision(ctx context.Context, postID string, userID string, n int32) (common.PostCardDTO, error)
 }
 
+var (
+	ErrPostNotFound       = errors.New("post not found")
+	ErrNotPostAuthor      = errors.New("unauthorized: user does not own the post")
+	ErrDeletedByModerator = errors.New("post was deleted by a moderator and can't be restored")
+	ErrRevisionNotFound   = errors.New("revision not found")
+	ErrSlugTaken          = errors.New("slug is already in use")
+)
+
+// Sort orders accepted by the post listing
+const (
+	SortRelevance = "relevance"
+	SortRecent    = "recent"
+)
+
+// Sort orders accepted by the comment listing
+const (
+	CommentSortNewest    = "newest"
+	CommentSortOldest    = "oldest"
+	CommentSortMostLiked = "most_liked"
+)
+
 type PaginatedResponse struct {
-	Posts   []common.PostCardDTO `json:"posts"`
-	Page    int                  `json:"page"`
-	Limit   int                  `json:"limit"`
-	HasMore bool                 `json:"hasMore"`
+	Posts      []common.PostCardDTO `json:"posts"`
+	Page       int                  `json:"page,omitempty"`
+	Limit      int                  `json:"limit"`
+	HasMore    bool                 `json:"hasMore"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	Highlights map[string]string    `json:"highlights,omitempty"` // post ID -> highlighted search snippet
+}
+
+// PaginatedCommentsResponse pages through top-level comments; each carries its full reply thread
+type PaginatedCommentsResponse struct {
+	Comments []common.CommentDTO `json:"comments"`
+	Page     int                 `json:"page"`
+	Limit    int                 `json:"limit"`
+	Total    int64               `json:"total"` // number of top-level comments
+	HasMore  bool                `json:"hasMore"`
 }
 
 type PostLikeRequest struct {
@@ -32,7 +75,24 @@ type PostLikeRequest struct {
 }
 
 type CreateUpdatePostRequest struct {
-	Title       string   `json:"title" validate:"required,min=3,max=200"`
-	Body        string   `json:"body" validate:"required,min=10"`
-	CategoryIDs []string `json:"category_ids" validate:"required,min=1,max=3,dive,uuid"`
+	Title       string     `json:"title" validate:"required,min=3,max=200"`
+	Body        string     `json:"body" validate:"required,min=10"`
+	Slug        string     `json:"slug" validate:"omitempty,min=3,max=100,slug"` // keeps the current slug when empty; reserved words like "feed" are rejected
+	CategoryIDs []string   `json:"category_ids" validate:"required,min=1,max=3,dive,uuid"`
+	IsDraft     *bool      `json:"is_draft"`
+	PublishAt   *time.Time `json:"publish_at"`
+}
+
+type PostRevisionDTO struct {
+	RevisionNumber int32     `json:"revision_number"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PostRevisionResponse is a revision with its line diff against the previous revision
+type PostRevisionResponse struct {
+	PostRevisionDTO
+	TitleDiff []utils.DiffLine `json:"title_diff"`
+	BodyDiff  []utils.DiffLine `json:"body_diff"`
 }
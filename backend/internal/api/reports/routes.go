@@ -0,0 +1,134 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/api/moderation"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+	logger  *zap.SugaredLogger
+	repo    *sqlc.Queries
+}
+
+func NewHandler(service Service, logger *zap.SugaredLogger, repo *sqlc.Queries) *handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+		repo:    repo,
+	}
+}
+
+func (h *handler) HandleCreateReport(w http.ResponseWriter, r *http.Request) {
+	var payload CreateReportRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request payload: %s", err.Error()))
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, _ := utils.GetUserFromContext(r.Context())
+
+	report, err := h.service.createReport(r.Context(), user.ID, payload)
+	switch {
+	case errors.Is(err, ErrTargetNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrAlreadyReported):
+		utils.WriteError(w, http.StatusConflict, err)
+		return
+	case err != nil:
+		h.logger.Errorf("failed to create report: %s", err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to create report"))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, report)
+}
+
+// HandleGetReports lists the moderation queue. ?status and ?target_type filter it; both default to all.
+func (h *handler) HandleGetReports(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	targetType := r.URL.Query().Get("target_type")
+	if !common.IsValidFilter(status, ReportStatusOpen, ReportStatusResolved, ReportStatusDismissed) ||
+		!common.IsValidFilter(targetType, common.ModerationTargetPost, common.ModerationTargetComment, common.ModerationTargetUser) {
+		utils.WriteError(w, http.StatusBadRequest, ErrInvalidFilter)
+		return
+	}
+
+	page, limit, offset := common.GetPaginationParams(r)
+
+	reports, hasMore, err := h.service.getReports(r.Context(), status, targetType, limit, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, ReportsResponse{
+		Reports: reports,
+		Page:    page,
+		Limit:   limit,
+		HasMore: hasMore,
+	})
+}
+
+func (h *handler) HandleGetReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.getReportByID(r.Context(), chi.URLParam(r, "reportID"))
+	if errors.Is(err, ErrReportNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, report)
+}
+
+func (h *handler) HandleResolveReport(w http.ResponseWriter, r *http.Request) {
+	var payload ResolveReportRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request payload: %s", err.Error()))
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	moderator, _ := utils.GetUserFromContext(r.Context())
+
+	report, err := h.service.resolveReport(r.Context(), moderator, chi.URLParam(r, "reportID"), payload.Action, payload.Reason)
+	switch {
+	case errors.Is(err, ErrReportNotFound), errors.Is(err, ErrTargetNotFound):
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, ErrReportAlreadyResolved):
+		utils.WriteError(w, http.StatusConflict, err)
+		return
+	case errors.Is(err, ErrInvalidResolution):
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, moderation.ErrCannotSuspendUser):
+		utils.WriteError(w, http.StatusForbidden, err)
+		return
+	case err != nil:
+		h.logger.Errorf("failed to resolve report: %s", err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to resolve report"))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, report)
+}
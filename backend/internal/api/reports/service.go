@@ -0,0 +1,252 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/api/moderation"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+// uniqueViolation is the Postgres error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+type svc struct {
+	repo *sqlc.Queries
+	db   *pgxpool.Pool
+}
+
+// NewService creates the reports service. Resolving a report hides content and suspends authors
+// with the same common helpers the posts, comments and moderation services use, so the actions
+// are logged the same way but share the report's transaction.
+func NewService(repo *sqlc.Queries, db *pgxpool.Pool) Service {
+	return &svc{
+		repo: repo,
+		db:   db,
+	}
+}
+
+func (s *svc) createReport(ctx context.Context, reporterID pgtype.UUID, payload CreateReportRequest) (ReportDTO, error) {
+	targetID, err := utils.StrToUUID(payload.TargetID)
+	if err != nil {
+		return ReportDTO{}, ErrTargetNotFound
+	}
+
+	// readers can only report what they can see
+	if _, err := getTargetAuthorID(ctx, s.repo, payload.TargetType, targetID, &reporterID); err != nil {
+		return ReportDTO{}, err
+	}
+
+	report, err := s.repo.CreateReport(ctx, sqlc.CreateReportParams{
+		ReporterID: reporterID,
+		TargetType: payload.TargetType,
+		TargetID:   targetID,
+		Reason:     payload.Reason,
+		Details:    payload.Details,
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ReportDTO{}, ErrAlreadyReported
+	}
+	if err != nil {
+		return ReportDTO{}, fmt.Errorf("failed to create report: %s", err.Error())
+	}
+	return newReportDTO(report), nil
+}
+
+// getReports returns a page of reports, oldest first, optionally filtered by status and target type
+func (s *svc) getReports(ctx context.Context, status string, targetType string, limit, offset int) ([]ReportDTO, bool, error) {
+	// fetch one extra row to know if there is another page
+	reports, err := s.repo.GetReports(ctx, sqlc.GetReportsParams{
+		Status:     pgtype.Text{String: status, Valid: status != ""},
+		TargetType: pgtype.Text{String: targetType, Valid: targetType != ""},
+		Limit:      int32(limit + 1),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get reports: %s", err.Error())
+	}
+
+	hasMore := len(reports) > limit
+	if hasMore {
+		reports = reports[:limit]
+	}
+
+	result := make([]ReportDTO, len(reports))
+	for i, report := range reports {
+		result[i] = newReportDTO(report)
+	}
+	return result, hasMore, nil
+}
+
+func (s *svc) getReportByID(ctx context.Context, reportID string) (ReportDTO, error) {
+	report, err := s.getReport(ctx, reportID)
+	if err != nil {
+		return ReportDTO{}, err
+	}
+	return newReportDTO(report), nil
+}
+
+// resolveReport applies a moderator's decision to an open report. The decision also
+// closes every other open report on the same target, since they would all be resolved the same way.
+func (s *svc) resolveReport(ctx context.Context, moderator sqlc.User, reportID string, action string, reason string) (ReportDTO, error) {
+	reportIDUUID, err := utils.StrToUUID(reportID)
+	if err != nil {
+		return ReportDTO{}, ErrReportNotFound
+	}
+
+	// the report stays locked until the action and the resolution are both saved, so two
+	// moderators can't act on the same report at once
+	err = common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		report, err := q.GetReportByIDForUpdate(ctx, reportIDUUID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReportNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get report: %s", err.Error())
+		}
+		if report.Status != ReportStatusOpen {
+			return ErrReportAlreadyResolved
+		}
+
+		status := ReportStatusResolved
+		switch action {
+		case ResolutionDismiss:
+			status = ReportStatusDismissed
+		case ResolutionHideContent:
+			if err := hideTarget(ctx, q, moderator, report, reason); err != nil {
+				return err
+			}
+		case ResolutionSuspendAuthor:
+			if err := suspendTargetAuthor(ctx, q, moderator, report, reason); err != nil {
+				return err
+			}
+		default:
+			return ErrInvalidResolution
+		}
+
+		err = q.ResolveReportsByTarget(ctx, sqlc.ResolveReportsByTargetParams{
+			TargetType: report.TargetType,
+			TargetID:   report.TargetID,
+			Status:     status,
+			Resolution: pgtype.Text{String: action, Valid: true},
+			ResolvedBy: moderator.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve reports: %s", err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return ReportDTO{}, err
+	}
+
+	return s.getReportByID(ctx, reportID)
+}
+
+// hideTarget hides a reported post or comment. Users have no content of their own to hide.
+func hideTarget(ctx context.Context, q *sqlc.Queries, moderator sqlc.User, report sqlc.Report, reason string) error {
+	var err error
+	switch report.TargetType {
+	case common.ModerationTargetPost:
+		_, err = common.SetPostHidden(ctx, q, moderator.ID, report.TargetID, true, reason)
+	case common.ModerationTargetComment:
+		_, err = common.SetCommentHidden(ctx, q, moderator.ID, report.TargetID, true, reason)
+	default:
+		return ErrInvalidResolution
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrTargetNotFound
+	}
+	return err
+}
+
+// suspendTargetAuthor suspends the author of a reported post or comment, or the reported user.
+// Like a direct suspension, moderators can only suspend users with a lower role.
+func suspendTargetAuthor(ctx context.Context, q *sqlc.Queries, moderator sqlc.User, report sqlc.Report, reason string) error {
+	authorID, err := getTargetAuthorID(ctx, q, report.TargetType, report.TargetID, nil)
+	if err != nil {
+		return err
+	}
+	author, err := q.GetUserByID(ctx, authorID)
+	if err != nil {
+		return ErrTargetNotFound
+	}
+	if utils.HasRole(author.Role, moderator.Role) {
+		return moderation.ErrCannotSuspendUser
+	}
+	_, err = common.SetUserSuspended(ctx, q, moderator.ID, author.ID, true, reason)
+	return err
+}
+
+// getTargetAuthorID returns the user responsible for a report target: the author of a post
+// or comment, or the reported user. When viewerID is set the target must also be visible to them.
+func getTargetAuthorID(ctx context.Context, q *sqlc.Queries, targetType string, targetID pgtype.UUID, viewerID *pgtype.UUID) (pgtype.UUID, error) {
+	switch targetType {
+	case common.ModerationTargetPost:
+		post, err := q.GetPostByID(ctx, targetID)
+		if err != nil || (viewerID != nil && !common.CanViewPost(post, viewerID)) {
+			return pgtype.UUID{}, ErrTargetNotFound
+		}
+		return post.AuthorID, nil
+	case common.ModerationTargetComment:
+		comment, err := q.GetCommentByID(ctx, targetID)
+		if err != nil || comment.IsDeleted {
+			return pgtype.UUID{}, ErrTargetNotFound
+		}
+		return comment.UserID, nil
+	case common.ModerationTargetUser:
+		user, err := q.GetUserByID(ctx, targetID)
+		if err != nil {
+			return pgtype.UUID{}, ErrTargetNotFound
+		}
+		return user.ID, nil
+	}
+	return pgtype.UUID{}, ErrTargetNotFound
+}
+
+func (s *svc) getReport(ctx context.Context, reportID string) (sqlc.Report, error) {
+	reportIDUUID, err := utils.StrToUUID(reportID)
+	if err != nil {
+		return sqlc.Report{}, ErrReportNotFound
+	}
+	report, err := s.repo.GetReportByID(ctx, reportIDUUID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Report{}, ErrReportNotFound
+	}
+	if err != nil {
+		return sqlc.Report{}, fmt.Errorf("failed to get report: %s", err.Error())
+	}
+	return report, nil
+}
+
+func newReportDTO(report sqlc.Report) ReportDTO {
+	dto := ReportDTO{
+		ID:         report.ID.String(),
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID.String(),
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		Resolution: report.Resolution.String,
+		CreatedAt:  report.CreatedAt.Time,
+	}
+	if report.ReporterID.Valid {
+		dto.ReporterID = report.ReporterID.String()
+	}
+	if report.ResolvedBy.Valid {
+		dto.ResolvedBy = report.ResolvedBy.String()
+	}
+	if report.ResolvedAt.Valid {
+		resolvedAt := report.ResolvedAt.Time
+		dto.ResolvedAt = &resolvedAt
+	}
+	return dto
+}
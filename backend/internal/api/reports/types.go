@@ -0,0 +1,75 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+)
+
+type Service interface {
+	createReport(ctx context.Context, reporterID pgtype.UUID, payload CreateReportRequest) (ReportDTO, error)
+	getReports(ctx context.Context, status string, targetType string, limit, offset int) ([]ReportDTO, bool, error)
+	getReportByID(ctx context.Context, reportID string) (ReportDTO, error)
+	resolveReport(ctx context.Context, moderator sqlc.User, reportID string, action string, reason string) (ReportDTO, error)
+}
+
+var (
+	ErrReportNotFound        = errors.New("report not found")
+	ErrTargetNotFound        = errors.New("reported content not found")
+	ErrAlreadyReported       = errors.New("you have already reported this")
+	ErrReportAlreadyResolved = errors.New("report has already been resolved")
+	ErrInvalidResolution     = errors.New("this action does not apply to the reported content")
+	ErrInvalidFilter         = errors.New("invalid status or target_type filter")
+)
+
+// Report statuses
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Actions a moderator can resolve a report with
+const (
+	ResolutionDismiss       = "dismiss"
+	ResolutionHideContent   = "hide_content"
+	ResolutionSuspendAuthor = "suspend_author"
+)
+
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" validate:"required,oneof=post comment user"`
+	TargetID   string `json:"target_id" validate:"required,uuid"`
+	Reason     string `json:"reason" validate:"required,oneof=spam harassment hate_speech misinformation inappropriate other"`
+	Details    string `json:"details" validate:"max=1000"`
+}
+
+type ResolveReportRequest struct {
+	Action string `json:"action" validate:"required,oneof=dismiss hide_content suspend_author"`
+	common.ModerationRequest
+}
+
+// ReportDTO is a report as seen by moderators. ReporterID and ResolvedBy are empty once those accounts are deleted.
+type ReportDTO struct {
+	ID         string     `json:"id"`
+	ReporterID string     `json:"reporter_id"`
+	TargetType string     `json:"target_type"`
+	TargetID   string     `json:"target_id"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details"`
+	Status     string     `json:"status"`
+	Resolution string     `json:"resolution,omitempty"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ReportsResponse struct {
+	Reports []ReportDTO `json:"reports"`
+	Page    int         `json:"page"`
+	Limit   int         `json:"limit"`
+	HasMore bool        `json:"hasMore"`
+}
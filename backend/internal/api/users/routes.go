@@ -1,8 +1,10 @@
 package users
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -55,7 +57,13 @@ func (h *handler) HandleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, user)
+	profile, err := h.service.getCurrentUserProfile(r.Context(), user)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch user profile: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, profile)
 }
 
 func (h *handler) HandleGetUserByID(w http.ResponseWriter, r *http.Request) {
@@ -74,7 +82,13 @@ func (h *handler) HandleGetUserByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, user)
+	profile, err := h.service.getUserProfile(r.Context(), user, h.getRequestingUserID(w, r))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch user profile: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, profile)
 }
 
 func (h *handler) HandleGetUserByUsername(w http.ResponseWriter, r *http.Request) {
@@ -83,10 +97,20 @@ func (h *handler) HandleGetUserByUsername(w http.ResponseWriter, r *http.Request
 	// Fetch user details from the database
 	user, err := h.service.getUserByUsername(r.Context(), pgtype.Text{String: username, Valid: username != ""})
 	if err != nil {
+		if h.redirectOldUsername(w, r, username) {
+			return
+		}
 		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("user not found"))
 		return
 	}
-	utils.WriteJSON(w, http.StatusOK, user)
+
+	profile, err := h.service.getUserProfile(r.Context(), user, h.getRequestingUserID(w, r))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch user profile: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, profile)
 }
 
 func (h *handler) HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -122,14 +146,53 @@ func (h *handler) HandleUpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Update user description in the database
-	updatedUser, err := h.service.updateUserDescription(r.Context(), userIDUUID, pgtype.Text{String: payload.Description, Valid: payload.Description != ""})
+	// Update user profile in the database
+	updatedUser, err := h.service.updateUserProfile(r.Context(), userIDUUID, payload)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update user description: %s", err.Error()))
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update user profile: %s", err.Error()))
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, updatedUser)
+	utils.WriteJSON(w, http.StatusOK, common.NewPrivateUserDTO(updatedUser))
+}
+
+func (h *handler) HandleUpdateUsername(w http.ResponseWriter, r *http.Request) {
+	var payload UpdateUsernameRequest
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request payload: %s", err.Error()))
+		return
+	}
+
+	// validate payload
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid username: use %d-%d lowercase letters, digits, underscores or hyphens, and not a reserved name", utils.UsernameMinLength, utils.UsernameMaxLength))
+		return
+	}
+
+	// Get user ID from context (set by authentication middleware)
+	userID, ok := utils.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("access token in header invalid"))
+		return
+	}
+
+	userIDUUID, err := utils.StrToUUID(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID in token: %s", err.Error()))
+		return
+	}
+
+	updatedUser, err := h.service.updateUsername(r.Context(), userIDUUID, payload.Username)
+	if errors.Is(err, ErrUsernameTaken) {
+		utils.WriteError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to update username: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, common.NewPrivateUserDTO(updatedUser))
 }
 
 func (h *handler) HandleDeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
@@ -179,6 +242,9 @@ func (h *handler) HandleGetUserPosts(w http.ResponseWriter, r *http.Request) {
 	// verify user exists
 	_, err := h.service.getUserByUsername(r.Context(), pgtype.Text{String: username, Valid: username != ""})
 	if err != nil {
+		if h.redirectOldUsername(w, r, username) {
+			return
+		}
 		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("user not found"))
 		return
 	}
@@ -186,21 +252,128 @@ func (h *handler) HandleGetUserPosts(w http.ResponseWriter, r *http.Request) {
 	// check if requester is authenticated
 	requestingUserID := h.getRequestingUserID(w, r)
 
-	posts, err := h.service.getPostsByUsername(r.Context(), pgtype.Text{String: username, Valid: username != ""}, requestingUserID)
+	cursor, ok := h.parseCursor(w, r)
+	if !ok {
+		return
+	}
+	page, limit, offset := common.GetPaginationParams(r)
+	usernameText := pgtype.Text{String: username, Valid: username != ""}
+
+	posts, nextCursor, err := h.service.getPostsByUsername(r.Context(), usernameText, cursor, limit, offset, requestingUserID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch user posts: %s", err.Error()))
 		return
 	}
 
+	if cursor != nil {
+		writeCursorPage(w, posts, nextCursor)
+		return
+	}
+
+	total, err := h.service.countPostsByUsername(r.Context(), usernameText, requestingUserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch user posts: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, common.PaginatedPosts{
+		Posts:   posts,
+		Page:    page,
+		Limit:   limit,
+		Total:   total,
+		HasMore: int64(offset+len(posts)) < total,
+	})
+}
+
+func (h *handler) HandleGetDrafts(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by authentication middleware)
+	userID, ok := utils.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("access token in header invalid"))
+		return
+	}
+
+	userIDUUID, err := utils.StrToUUID(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID in token: %s", err.Error()))
+		return
+	}
+
+	posts, err := h.service.getDraftPosts(r.Context(), userIDUUID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch drafts: %s", err.Error()))
+		return
+	}
+
 	utils.WriteJSON(w, http.StatusOK, posts)
 }
 
+func (h *handler) HandleGetSessions(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by authentication middleware)
+	userID, ok := utils.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("access token in header invalid"))
+		return
+	}
+
+	userIDUUID, err := utils.StrToUUID(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID in token: %s", err.Error()))
+		return
+	}
+
+	currentSessionID, _ := utils.GetSessionIDFromContext(r.Context())
+	sessions, err := h.service.getSessions(r.Context(), userIDUUID, currentSessionID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch sessions: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, sessions)
+}
+
+func (h *handler) HandleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by authentication middleware)
+	userID, ok := utils.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("access token in header invalid"))
+		return
+	}
+
+	userIDUUID, err := utils.StrToUUID(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID in token: %s", err.Error()))
+		return
+	}
+
+	sessionIDUUID, err := utils.StrToUUID(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid session ID: %s", err.Error()))
+		return
+	}
+
+	err = h.service.revokeSession(r.Context(), userIDUUID, sessionIDUUID)
+	if errors.Is(err, ErrSessionNotFound) {
+		utils.WriteError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to revoke session: %s", err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *handler) HandleGetLikedPosts(w http.ResponseWriter, r *http.Request) {
 	username := chi.URLParam(r, "username")
 
 	// verify user exists
 	_, err := h.service.getUserByUsername(r.Context(), pgtype.Text{String: username, Valid: username != ""})
 	if err != nil {
+		if h.redirectOldUsername(w, r, username) {
+			return
+		}
 		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("user not found"))
 		return
 	}
@@ -208,11 +381,267 @@ func (h *handler) HandleGetLikedPosts(w http.ResponseWriter, r *http.Request) {
 	// check if requester is authenticated
 	requestingUserID := h.getRequestingUserID(w, r)
 
-	posts, err := h.service.getLikedPostsByUsername(r.Context(), pgtype.Text{String: username, Valid: username != ""}, requestingUserID)
+	cursor, ok := h.parseCursor(w, r)
+	if !ok {
+		return
+	}
+	page, limit, offset := common.GetPaginationParams(r)
+	usernameText := pgtype.Text{String: username, Valid: username != ""}
+
+	posts, nextCursor, err := h.service.getLikedPostsByUsername(r.Context(), usernameText, cursor, limit, offset, requestingUserID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch liked posts: %s", err.Error()))
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, posts)
+	if cursor != nil {
+		writeCursorPage(w, posts, nextCursor)
+		return
+	}
+
+	total, err := h.service.countLikedPostsByUsername(r.Context(), usernameText, requestingUserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch liked posts: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, common.PaginatedPosts{
+		Posts:   posts,
+		Page:    page,
+		Limit:   limit,
+		Total:   total,
+		HasMore: int64(offset+len(posts)) < total,
+	})
+}
+
+func (h *handler) HandleFollowUser(w http.ResponseWriter, r *http.Request) {
+	h.handleFollow(w, r, true)
+}
+
+func (h *handler) HandleUnfollowUser(w http.ResponseWriter, r *http.Request) {
+	h.handleFollow(w, r, false)
+}
+
+// handleFollow follows or unfollows the user in the URL on behalf of the authenticated user
+func (h *handler) handleFollow(w http.ResponseWriter, r *http.Request, follow bool) {
+	followerIDUUID, followeeIDUUID, ok := h.relationUserIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	if follow {
+		err = h.service.followUser(r.Context(), followerIDUUID, followeeIDUUID)
+	} else {
+		err = h.service.unfollowUser(r.Context(), followerIDUUID, followeeIDUUID)
+	}
+	if errors.Is(err, common.ErrBlocked) {
+		utils.WriteError(w, http.StatusForbidden, err)
+		return
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]bool{"following": follow})
+}
+
+func (h *handler) HandleBlockUser(w http.ResponseWriter, r *http.Request) {
+	h.handleBlock(w, r, true)
+}
+
+func (h *handler) HandleUnblockUser(w http.ResponseWriter, r *http.Request) {
+	h.handleBlock(w, r, false)
+}
+
+// handleBlock blocks or unblocks the user in the URL on behalf of the authenticated user
+func (h *handler) handleBlock(w http.ResponseWriter, r *http.Request, block bool) {
+	blockerIDUUID, blockedIDUUID, ok := h.relationUserIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	if block {
+		err = h.service.blockUser(r.Context(), blockerIDUUID, blockedIDUUID)
+	} else {
+		err = h.service.unblockUser(r.Context(), blockerIDUUID, blockedIDUUID)
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]bool{"blocked": block})
+}
+
+func (h *handler) HandleMuteUser(w http.ResponseWriter, r *http.Request) {
+	h.handleMute(w, r, true)
+}
+
+func (h *handler) HandleUnmuteUser(w http.ResponseWriter, r *http.Request) {
+	h.handleMute(w, r, false)
+}
+
+// handleMute mutes or unmutes the user in the URL on behalf of the authenticated user
+func (h *handler) handleMute(w http.ResponseWriter, r *http.Request, mute bool) {
+	muterIDUUID, mutedIDUUID, ok := h.relationUserIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	if mute {
+		err = h.service.muteUser(r.Context(), muterIDUUID, mutedIDUUID)
+	} else {
+		err = h.service.unmuteUser(r.Context(), muterIDUUID, mutedIDUUID)
+	}
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, map[string]bool{"muted": mute})
+}
+
+// relationUserIDs returns the authenticated user's ID and the ID of the existing user in the URL.
+// On failure it writes the error response and returns false.
+func (h *handler) relationUserIDs(w http.ResponseWriter, r *http.Request) (pgtype.UUID, pgtype.UUID, bool) {
+	// Get user ID from context (set by authentication middleware)
+	userID, ok := utils.GetUserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("access token in header invalid"))
+		return pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	userIDUUID, err := utils.StrToUUID(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID in token: %s", err.Error()))
+		return pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	targetIDUUID, err := utils.StrToUUID(chi.URLParam(r, "userID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user ID: %s", err.Error()))
+		return pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	// verify the target user exists
+	if _, err := h.service.getUserByID(r.Context(), targetIDUUID); err != nil {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("user not found"))
+		return pgtype.UUID{}, pgtype.UUID{}, false
+	}
+
+	return userIDUUID, targetIDUUID, true
+}
+
+func (h *handler) HandleGetFollowers(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+	page, limit, offset := common.GetPaginationParams(r)
+
+	// verify user exists
+	user, err := h.service.getUserByUsername(r.Context(), pgtype.Text{String: username, Valid: username != ""})
+	if err != nil {
+		if h.redirectOldUsername(w, r, username) {
+			return
+		}
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("user not found"))
+		return
+	}
+
+	followers, err := h.service.getFollowers(r.Context(), user.ID, limit, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch followers: %s", err.Error()))
+		return
+	}
+
+	total, err := h.service.countFollowers(r.Context(), user.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch followers: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, FollowListResponse{
+		Users:   common.NewPublicUserDTOs(followers),
+		Page:    page,
+		Limit:   limit,
+		Total:   total,
+		HasMore: int64(offset+len(followers)) < total,
+	})
+}
+
+func (h *handler) HandleGetFollowing(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+	page, limit, offset := common.GetPaginationParams(r)
+
+	// verify user exists
+	user, err := h.service.getUserByUsername(r.Context(), pgtype.Text{String: username, Valid: username != ""})
+	if err != nil {
+		if h.redirectOldUsername(w, r, username) {
+			return
+		}
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("user not found"))
+		return
+	}
+
+	following, err := h.service.getFollowing(r.Context(), user.ID, limit, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch following: %s", err.Error()))
+		return
+	}
+
+	total, err := h.service.countFollowing(r.Context(), user.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to fetch following: %s", err.Error()))
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, FollowListResponse{
+		Users:   common.NewPublicUserDTOs(following),
+		Page:    page,
+		Limit:   limit,
+		Total:   total,
+		HasMore: int64(offset+len(following)) < total,
+	})
+}
+
+// redirectOldUsername redirects a request for a username the user has since changed to the
+// same path under their current username. Returns false when the username was never used.
+func (h *handler) redirectOldUsername(w http.ResponseWriter, r *http.Request, username string) bool {
+	currentUsername, err := h.service.getCurrentUsername(r.Context(), username)
+	if err != nil {
+		return false
+	}
+
+	location := strings.Replace(r.URL.Path, "/u/"+username, "/u/"+currentUsername, 1)
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	w.Header().Set("Location", location)
+	utils.WriteJSON(w, http.StatusMovedPermanently, map[string]string{"username": currentUsername})
+	return true
+}
+
+// parseCursor decodes the optional cursor query param. A nil cursor means the
+// client did not ask for cursor pagination. Writes a 400 and returns false on a bad cursor.
+func (h *handler) parseCursor(w http.ResponseWriter, r *http.Request) (*common.Cursor, bool) {
+	if !r.URL.Query().Has("cursor") {
+		return nil, true
+	}
+	cursorPublishedAt, cursorID, err := common.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return &common.Cursor{PublishedAt: cursorPublishedAt, ID: cursorID}, true
+}
+
+// writeCursorPage wraps a keyset-paginated page of posts in the cursor envelope
+func writeCursorPage(w http.ResponseWriter, posts []common.PostCardDTO, nextCursor string) {
+	utils.WriteJSON(w, http.StatusOK, common.CursorPaginatedPosts{
+		Posts:      posts,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	})
 }
@@ -3,6 +3,7 @@ package users
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -38,39 +39,438 @@ func (s *svc) getUserByUsername(ctx context.Context, username pgtype.Text) (sqlc
 	return user, nil
 }
 
-func (s *svc) updateUserDescription(ctx context.Context, userID pgtype.UUID, description pgtype.Text) (sqlc.User, error) {
-	user, err := s.repo.UpdateUser(ctx, sqlc.UpdateUserParams{
-		ID:          userID,
-		Description: description,
-	})
+func (s *svc) getUserProfile(ctx context.Context, user sqlc.User, requestingUserID *pgtype.UUID) (UserProfileResponse, error) {
+	counts, err := s.repo.GetFollowCountsByUserID(ctx, user.ID)
+	if err != nil {
+		return UserProfileResponse{}, fmt.Errorf("failed to get follow counts: %s", err.Error())
+	}
+
+	profile := UserProfileResponse{
+		PublicUserDTO:  common.NewPublicUserDTO(user),
+		FollowerCount:  counts.FollowerCount,
+		FollowingCount: counts.FollowingCount,
+	}
+
+	// check if the requesting user follows this user
+	if requestingUserID != nil && requestingUserID.Valid && *requestingUserID != user.ID {
+		isFollowing, err := s.repo.IsFollowingUser(ctx, sqlc.IsFollowingUserParams{
+			FollowerID: *requestingUserID,
+			FolloweeID: user.ID,
+		})
+		if err != nil {
+			return UserProfileResponse{}, fmt.Errorf("failed to get follow status: %s", err.Error())
+		}
+		profile.IsFollowing = isFollowing
+	}
+
+	return profile, nil
+}
+
+func (s *svc) getCurrentUserProfile(ctx context.Context, user sqlc.User) (CurrentUserResponse, error) {
+	counts, err := s.repo.GetFollowCountsByUserID(ctx, user.ID)
 	if err != nil {
-		return sqlc.User{}, fmt.Errorf("failed to update user description: %s", err.Error())
+		return CurrentUserResponse{}, fmt.Errorf("failed to get follow counts: %s", err.Error())
+	}
+
+	return CurrentUserResponse{
+		PrivateUserDTO: common.NewPrivateUserDTO(user),
+		FollowerCount:  counts.FollowerCount,
+		FollowingCount: counts.FollowingCount,
+	}, nil
+}
+
+// updateUserProfile applies the fields set in the payload on top of the user's current profile
+func (s *svc) updateUserProfile(ctx context.Context, userID pgtype.UUID, payload UpdateUserRequest) (sqlc.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return sqlc.User{}, fmt.Errorf("failed to get user: %s", err.Error())
+	}
+
+	params := sqlc.UpdateUserParams{
+		ID:              userID,
+		Description:     textOrCurrent(payload.Description, user.Description),
+		DisplayName:     textOrCurrent(payload.DisplayName, user.DisplayName),
+		WebsiteUrl:      textOrCurrent(payload.WebsiteURL, user.WebsiteUrl),
+		GithubUrl:       textOrCurrent(payload.GithubURL, user.GithubUrl),
+		TwitterUrl:      textOrCurrent(payload.TwitterURL, user.TwitterUrl),
+		LinkedinUrl:     textOrCurrent(payload.LinkedinURL, user.LinkedinUrl),
+		Location:        textOrCurrent(payload.Location, user.Location),
+		CustomAvatarUrl: textOrCurrent(payload.AvatarURL, user.CustomAvatarUrl),
+		ShowEmail:       user.ShowEmail,
+	}
+	if payload.ShowEmail != nil {
+		params.ShowEmail = *payload.ShowEmail
+	}
+
+	user, err = s.repo.UpdateUser(ctx, params)
+	if err != nil {
+		return sqlc.User{}, fmt.Errorf("failed to update user profile: %s", err.Error())
 	}
 	return user, nil
 }
 
-func (s *svc) getPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, error) {
-	posts, err := s.repo.GetPostsByUsername(ctx, username)
+// textOrCurrent keeps current when the field was omitted; an empty value clears it
+func textOrCurrent(value *string, current pgtype.Text) pgtype.Text {
+	if value == nil {
+		return current
+	}
+	trimmed := strings.TrimSpace(*value)
+	return pgtype.Text{String: trimmed, Valid: trimmed != ""}
+}
+
+// updateUsername renames the user. The old username is kept in the history so its profile
+// links redirect, and stays reserved for this user so nobody else can take it over.
+func (s *svc) updateUsername(ctx context.Context, userID pgtype.UUID, username string) (sqlc.User, error) {
+	var user sqlc.User
+	err := common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		taken, err := q.IsUsernameTaken(ctx, sqlc.IsUsernameTakenParams{
+			Username: username,
+			UserID:   userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check username: %s", err.Error())
+		}
+		if taken {
+			return ErrUsernameTaken
+		}
+
+		user, err = q.GetUserByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %s", err.Error())
+		}
+		if user.Username.String == username {
+			return nil
+		}
+
+		if user.Username.Valid {
+			err = q.CreateUsernameHistory(ctx, sqlc.CreateUsernameHistoryParams{
+				Username: user.Username.String,
+				UserID:   userID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record old username: %s", err.Error())
+			}
+		}
+		// taking back one of the user's own old usernames
+		err = q.DeleteUsernameHistory(ctx, sqlc.DeleteUsernameHistoryParams{
+			Username: username,
+			UserID:   userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update username history: %s", err.Error())
+		}
+
+		user, err = q.UpdateUsername(ctx, sqlc.UpdateUsernameParams{
+			ID:       userID,
+			Username: pgtype.Text{String: username, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update username: %s", err.Error())
+		}
+		return nil
+	})
+	return user, err
+}
+
+// getCurrentUsername returns the username a user has now, given one they used to have
+func (s *svc) getCurrentUsername(ctx context.Context, oldUsername string) (string, error) {
+	username, err := s.repo.GetCurrentUsernameByOldUsername(ctx, oldUsername)
+	if err != nil || !username.Valid {
+		return "", fmt.Errorf("user not found")
+	}
+	return username.String, nil
+}
+
+func (s *svc) getPostsByUsername(ctx context.Context, username pgtype.Text, cursor *common.Cursor, limit, offset int, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, string, error) {
+	params := sqlc.GetPostsByUsernameParams{
+		Username: username,
+		ViewerID: viewerID(requestingUserID),
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	}
+	if cursor != nil {
+		// fetch one extra row to know whether another page exists
+		params.CursorPublishedAt, params.CursorID = cursor.PublishedAt, cursor.ID
+		params.Limit = int32(limit + 1)
+		params.Offset = 0
+	}
+
+	posts, err := s.repo.GetPostsByUsername(ctx, params)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get posts by username: %s", err.Error())
+	}
+
+	nextCursor := ""
+	if cursor != nil {
+		posts, nextCursor = common.TrimPostsPage(posts, limit)
+	}
+
+	enriched, err := common.EnrichPostsWithDetails(ctx, s.repo, posts, requestingUserID)
+	if err != nil {
+		return nil, "", err
+	}
+	return enriched, nextCursor, nil
+}
+
+func (s *svc) countPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) (int64, error) {
+	total, err := s.repo.CountPostsByUsername(ctx, sqlc.CountPostsByUsernameParams{
+		Username: username,
+		ViewerID: viewerID(requestingUserID),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count posts: %s", err.Error())
+	}
+	return total, nil
+}
+
+func (s *svc) getLikedPostsByUsername(ctx context.Context, username pgtype.Text, cursor *common.Cursor, limit, offset int, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, string, error) {
+	params := sqlc.GetPostsLikedByUsernameParams{
+		Username: username,
+		ViewerID: viewerID(requestingUserID),
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	}
+	if cursor != nil {
+		// fetch one extra row to know whether another page exists
+		params.CursorPublishedAt, params.CursorID = cursor.PublishedAt, cursor.ID
+		params.Limit = int32(limit + 1)
+		params.Offset = 0
+	}
+
+	posts, err := s.repo.GetPostsLikedByUsername(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get posts by username: %s", err.Error())
+		return nil, "", fmt.Errorf("failed to get liked posts by username: %s", err.Error())
 	}
 
-	return common.EnrichPostsWithDetails(ctx, s.repo, posts, requestingUserID)
+	nextCursor := ""
+	if cursor != nil {
+		posts, nextCursor = common.TrimPostsPage(posts, limit)
+	}
+
+	enriched, err := common.EnrichPostsWithDetails(ctx, s.repo, posts, requestingUserID)
+	if err != nil {
+		return nil, "", err
+	}
+	return enriched, nextCursor, nil
 }
 
-func (s *svc) getLikedPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, error) {
-	posts, err := s.repo.GetPostsLikedByUsername(ctx, username)
+func (s *svc) countLikedPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) (int64, error) {
+	total, err := s.repo.CountPostsLikedByUsername(ctx, sqlc.CountPostsLikedByUsernameParams{
+		Username: username,
+		ViewerID: viewerID(requestingUserID),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get liked posts by username: %s", err.Error())
+		return 0, fmt.Errorf("failed to count liked posts: %s", err.Error())
 	}
+	return total, nil
+}
 
-	return common.EnrichPostsWithDetails(ctx, s.repo, posts, requestingUserID)
+func (s *svc) getDraftPosts(ctx context.Context, userID pgtype.UUID) ([]common.PostCardDTO, error) {
+	posts, err := s.repo.GetDraftPostsByAuthorID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft posts: %s", err.Error())
+	}
+
+	return common.EnrichPostsWithDetails(ctx, s.repo, posts, &userID)
 }
 
+// deleteUserByID deletes an account along with its posts and comments. They are marked as
+// deleted with the account, so signing in again within the restore window brings back exactly
+// what the account deletion removed. Comments that other users replied to become placeholders
+// instead, like deleting them one by one would, and are not restored.
 func (s *svc) deleteUserByID(ctx context.Context, userID pgtype.UUID) error {
-	err := s.repo.DeleteUserByID(ctx, userID)
+	return common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		// sign the user out everywhere along with the deletion
+		if err := q.RevokeSessionsByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %s", err.Error())
+		}
+		if err := q.SoftDeletePostsByAuthorID(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete posts: %s", err.Error())
+		}
+		if err := q.TombstoneCommentsWithRepliesByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete comments: %s", err.Error())
+		}
+		if err := q.SoftDeleteCommentsByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete comments: %s", err.Error())
+		}
+		if err := q.SoftDeleteUser(ctx, userID); err != nil {
+			return fmt.Errorf("failed to delete user: %s", err.Error())
+		}
+		return nil
+	})
+}
+
+func (s *svc) followUser(ctx context.Context, followerID pgtype.UUID, followeeID pgtype.UUID) error {
+	if followerID == followeeID {
+		return fmt.Errorf("you cannot follow yourself")
+	}
+	if err := common.CheckNotBlocked(ctx, s.repo, followeeID, followerID); err != nil {
+		return err
+	}
+
+	err := s.repo.FollowUser(ctx, sqlc.FollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to follow user: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *svc) unfollowUser(ctx context.Context, followerID pgtype.UUID, followeeID pgtype.UUID) error {
+	err := s.repo.UnfollowUser(ctx, sqlc.UnfollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unfollow user: %s", err.Error())
+	}
+	return nil
+}
+
+// blockUser blocks a user and removes any follow between the two of them
+func (s *svc) blockUser(ctx context.Context, blockerID pgtype.UUID, blockedID pgtype.UUID) error {
+	if blockerID == blockedID {
+		return fmt.Errorf("you cannot block yourself")
+	}
+
+	return common.ExecTx(ctx, s.db, func(q *sqlc.Queries) error {
+		if err := q.BlockUser(ctx, sqlc.BlockUserParams{BlockerID: blockerID, BlockedID: blockedID}); err != nil {
+			return fmt.Errorf("failed to block user: %s", err.Error())
+		}
+		if err := q.UnfollowUser(ctx, sqlc.UnfollowUserParams{FollowerID: blockerID, FolloweeID: blockedID}); err != nil {
+			return fmt.Errorf("failed to unfollow user: %s", err.Error())
+		}
+		if err := q.UnfollowUser(ctx, sqlc.UnfollowUserParams{FollowerID: blockedID, FolloweeID: blockerID}); err != nil {
+			return fmt.Errorf("failed to remove follower: %s", err.Error())
+		}
+		return nil
+	})
+}
+
+func (s *svc) unblockUser(ctx context.Context, blockerID pgtype.UUID, blockedID pgtype.UUID) error {
+	err := s.repo.UnblockUser(ctx, sqlc.UnblockUserParams{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unblock user: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *svc) muteUser(ctx context.Context, muterID pgtype.UUID, mutedID pgtype.UUID) error {
+	if muterID == mutedID {
+		return fmt.Errorf("you cannot mute yourself")
+	}
+
+	err := s.repo.MuteUser(ctx, sqlc.MuteUserParams{
+		MuterID: muterID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mute user: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *svc) unmuteUser(ctx context.Context, muterID pgtype.UUID, mutedID pgtype.UUID) error {
+	err := s.repo.UnmuteUser(ctx, sqlc.UnmuteUserParams{
+		MuterID: muterID,
+		MutedID: mutedID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unmute user: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *svc) getFollowers(ctx context.Context, userID pgtype.UUID, limit, offset int) ([]sqlc.User, error) {
+	followers, err := s.repo.GetFollowersByUserID(ctx, sqlc.GetFollowersByUserIDParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get followers: %s", err.Error())
+	}
+	if followers == nil {
+		followers = []sqlc.User{}
+	}
+	return followers, nil
+}
+
+func (s *svc) countFollowers(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	total, err := s.repo.CountFollowersByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count followers: %s", err.Error())
+	}
+	return total, nil
+}
+
+func (s *svc) getFollowing(ctx context.Context, userID pgtype.UUID, limit, offset int) ([]sqlc.User, error) {
+	following, err := s.repo.GetFollowingByUserID(ctx, sqlc.GetFollowingByUserIDParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get following: %s", err.Error())
+	}
+	if following == nil {
+		following = []sqlc.User{}
+	}
+	return following, nil
+}
+
+func (s *svc) countFollowing(ctx context.Context, userID pgtype.UUID) (int64, error) {
+	total, err := s.repo.CountFollowingByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count following: %s", err.Error())
+	}
+	return total, nil
+}
+
+// viewerID converts an optional requesting user into a nullable query parameter
+func viewerID(requestingUserID *pgtype.UUID) pgtype.UUID {
+	if requestingUserID == nil {
+		return pgtype.UUID{}
+	}
+	return *requestingUserID
+}
+
+func (s *svc) getSessions(ctx context.Context, userID pgtype.UUID, currentSessionID string) ([]SessionDTO, error) {
+	sessions, err := s.repo.GetActiveSessionsByUserID(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %s", err.Error())
+		return nil, fmt.Errorf("failed to get sessions: %s", err.Error())
+	}
+
+	result := make([]SessionDTO, len(sessions))
+	for i, session := range sessions {
+		result[i] = SessionDTO{
+			ID:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IpAddress,
+			CreatedAt:  session.CreatedAt.Time,
+			LastUsedAt: session.LastUsedAt.Time,
+			Current:    session.ID.String() == currentSessionID,
+		}
+	}
+	return result, nil
+}
+
+// revokeSession signs one of the user's sessions out; its tokens stop working immediately
+func (s *svc) revokeSession(ctx context.Context, userID pgtype.UUID, sessionID pgtype.UUID) error {
+	rows, err := s.repo.RevokeUserSession(ctx, sqlc.RevokeUserSessionParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %s", err.Error())
+	}
+	if rows == 0 {
+		return ErrSessionNotFound
 	}
 	return nil
 }
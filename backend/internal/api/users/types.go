@@ -2,6 +2,8 @@ package users
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
@@ -11,12 +13,83 @@ import (
 type Service interface {
 	getUserByID(ctx context.Context, userID pgtype.UUID) (sqlc.User, error)
 	getUserByUsername(ctx context.Context, username pgtype.Text) (sqlc.User, error)
-	updateUserDescription(ctx context.Context, userID pgtype.UUID, description pgtype.Text) (sqlc.User, error)
-	getPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, error)
-	getLikedPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, error)
+	getUserProfile(ctx context.Context, user sqlc.User, requestingUserID *pgtype.UUID) (UserProfileResponse, error)
+	getCurrentUserProfile(ctx context.Context, user sqlc.User) (CurrentUserResponse, error)
+	updateUserProfile(ctx context.Context, userID pgtype.UUID, payload UpdateUserRequest) (sqlc.User, error)
+	updateUsername(ctx context.Context, userID pgtype.UUID, username string) (sqlc.User, error)
+	getCurrentUsername(ctx context.Context, oldUsername string) (string, error)
+	getPostsByUsername(ctx context.Context, username pgtype.Text, cursor *common.Cursor, limit, offset int, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, string, error)
+	countPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) (int64, error)
+	getLikedPostsByUsername(ctx context.Context, username pgtype.Text, cursor *common.Cursor, limit, offset int, requestingUserID *pgtype.UUID) ([]common.PostCardDTO, string, error)
+	countLikedPostsByUsername(ctx context.Context, username pgtype.Text, requestingUserID *pgtype.UUID) (int64, error)
+	getDraftPosts(ctx context.Context, userID pgtype.UUID) ([]common.PostCardDTO, error)
 	deleteUserByID(ctx context.Context, userID pgtype.UUID) error
+	followUser(ctx context.Context, followerID pgtype.UUID, followeeID pgtype.UUID) error
+	unfollowUser(ctx context.Context, followerID pgtype.UUID, followeeID pgtype.UUID) error
+	blockUser(ctx context.Context, blockerID pgtype.UUID, blockedID pgtype.UUID) error
+	unblockUser(ctx context.Context, blockerID pgtype.UUID, blockedID pgtype.UUID) error
+	muteUser(ctx context.Context, muterID pgtype.UUID, mutedID pgtype.UUID) error
+	unmuteUser(ctx context.Context, muterID pgtype.UUID, mutedID pgtype.UUID) error
+	getFollowers(ctx context.Context, userID pgtype.UUID, limit, offset int) ([]sqlc.User, error)
+	getFollowing(ctx context.Context, userID pgtype.UUID, limit, offset int) ([]sqlc.User, error)
+	countFollowers(ctx context.Context, userID pgtype.UUID) (int64, error)
+	countFollowing(ctx context.Context, userID pgtype.UUID) (int64, error)
+	getSessions(ctx context.Context, userID pgtype.UUID, currentSessionID string) ([]SessionDTO, error)
+	revokeSession(ctx context.Context, userID pgtype.UUID, sessionID pgtype.UUID) error
 }
 
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrUsernameTaken   = errors.New("username is already taken")
+)
+
+// UpdateUserRequest edits the profile. Omitted fields are left unchanged and an empty string clears a field.
 type UpdateUserRequest struct {
-	Description string `json:"description"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+	DisplayName *string `json:"display_name" validate:"omitempty,max=50"`
+	WebsiteURL  *string `json:"website_url" validate:"omitempty,max=200,http_url"`
+	GithubURL   *string `json:"github_url" validate:"omitempty,max=200,http_url"`
+	TwitterURL  *string `json:"twitter_url" validate:"omitempty,max=200,http_url"`
+	LinkedinURL *string `json:"linkedin_url" validate:"omitempty,max=200,http_url"`
+	Location    *string `json:"location" validate:"omitempty,max=100"`
+	AvatarURL   *string `json:"avatar_url" validate:"omitempty,max=500,http_url"` // overrides the provider's avatar; empty restores it
+	ShowEmail   *bool   `json:"show_email"`                                       // shows the email on the public profile
+}
+
+// UpdateUsernameRequest sets a new username: 3-30 lowercase letters, digits, "_" or "-", not a reserved word
+type UpdateUsernameRequest struct {
+	Username string `json:"username" validate:"required,username"`
+}
+
+// UserProfileResponse is a user's public profile enriched with follow counts and the viewer's follow status
+type UserProfileResponse struct {
+	common.PublicUserDTO
+	FollowerCount  int64 `json:"follower_count"`
+	FollowingCount int64 `json:"following_count"`
+	IsFollowing    bool  `json:"is_following"`
+}
+
+// CurrentUserResponse is the signed-in user's own account with their follow counts
+type CurrentUserResponse struct {
+	common.PrivateUserDTO
+	FollowerCount  int64 `json:"follower_count"`
+	FollowingCount int64 `json:"following_count"`
+}
+
+type FollowListResponse struct {
+	Users   []common.PublicUserDTO `json:"users"`
+	Page    int                    `json:"page"`
+	Limit   int                    `json:"limit"`
+	Total   int64                  `json:"total"`
+	HasMore bool                   `json:"hasMore"`
+}
+
+// SessionDTO describes a signed-in device; Current marks the session making the request
+type SessionDTO struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
 }
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+)
+
+// ErrBlocked is returned when a user acts on the content of someone who has blocked them
+var ErrBlocked = errors.New("you have been blocked by this user")
+
+// CheckNotBlocked returns ErrBlocked when ownerID has blocked userID
+func CheckNotBlocked(ctx context.Context, repo *sqlc.Queries, ownerID, userID pgtype.UUID) error {
+	blocked, err := repo.IsBlockedByUser(ctx, sqlc.IsBlockedByUserParams{
+		BlockerID: ownerID,
+		BlockedID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check block: %s", err.Error())
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+// EncodeCursor builds an opaque pagination cursor from a row's (published_at, id) pair
+func EncodeCursor(publishedAt time.Time, id pgtype.UUID) string {
+	raw := publishedAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor. An empty cursor
+// decodes to invalid (NULL) values, meaning "start from the beginning".
+func DecodeCursor(cursor string) (pgtype.Timestamptz, pgtype.UUID, error) {
+	if cursor == "" {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, fmt.Errorf("invalid cursor")
+	}
+
+	publishedAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := utils.StrToUUID(parts[1])
+	if err != nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}, fmt.Errorf("invalid cursor")
+	}
+
+	return pgtype.Timestamptz{Time: publishedAt, Valid: true}, id, nil
+}
+
+// Cursor is a decoded keyset position in a listing ordered by (published_at, id)
+type Cursor struct {
+	PublishedAt pgtype.Timestamptz
+	ID          pgtype.UUID
+}
+
+// TrimPostsPage drops the extra row fetched beyond limit and returns the cursor
+// for the next page, or an empty cursor when this is the last page
+func TrimPostsPage(posts []sqlc.Post, limit int) ([]sqlc.Post, string) {
+	if len(posts) <= limit {
+		return posts, ""
+	}
+	posts = posts[:limit]
+	last := posts[len(posts)-1]
+	return posts, EncodeCursor(postSortTime(last), last.ID)
+}
+
+// postSortTime is the time a post is listed under. Drafts shown to their author on
+// their own profile have never been published and fall back to their creation time.
+func postSortTime(post sqlc.Post) time.Time {
+	if post.PublishedAt.Valid {
+		return post.PublishedAt.Time
+	}
+	return post.CreatedAt.Time
+}
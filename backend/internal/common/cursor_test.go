@@ -0,0 +1,71 @@
+package common
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id, err := utils.StrToUUID("3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b")
+	if err != nil {
+		t.Fatalf("StrToUUID: %v", err)
+	}
+	tests := []struct {
+		name        string
+		publishedAt time.Time
+	}{
+		{"utc", time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)},
+		{"nanoseconds", time.Date(2026, 3, 14, 15, 9, 26, 535897932, time.UTC)},
+		{"other time zone", time.Date(2026, 3, 14, 15, 9, 26, 0, time.FixedZone("IST", 5*3600+1800))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publishedAt, gotID, err := DecodeCursor(EncodeCursor(tt.publishedAt, id))
+			if err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+			if !publishedAt.Valid || !publishedAt.Time.Equal(tt.publishedAt) {
+				t.Errorf("publishedAt = %v, want %v", publishedAt.Time, tt.publishedAt)
+			}
+			if gotID != id {
+				t.Errorf("id = %s, want %s", gotID.String(), id.String())
+			}
+		})
+	}
+}
+
+func TestDecodeCursorEmpty(t *testing.T) {
+	publishedAt, id, err := DecodeCursor("")
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if publishedAt.Valid || id.Valid {
+		t.Errorf("DecodeCursor(\"\") = %v, %v, want NULL values", publishedAt, id)
+	}
+}
+
+func TestDecodeCursorMalformed(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"no separator", encode("2026-03-14T15:09:26Z")},
+		{"bad time", encode("yesterday|3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b")},
+		{"bad id", encode("2026-03-14T15:09:26Z|not-a-uuid")},
+		{"empty id", encode("2026-03-14T15:09:26Z|")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := DecodeCursor(tt.cursor); err == nil {
+				t.Errorf("DecodeCursor(%q) succeeded, want error", tt.cursor)
+			}
+		})
+	}
+}
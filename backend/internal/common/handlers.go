@@ -2,8 +2,10 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -13,18 +15,73 @@ import (
 
 // GetRequestingUserID extracts and validates the user ID from the request token
 func GetRequestingUserID(ctx context.Context, w http.ResponseWriter, r *http.Request, repo *sqlc.Queries) *pgtype.UUID {
-	token := utils.GetTokenFromRequest(r)
-	userIDUUID, err := utils.GetUserIDFromToken(w, token)
+	user, _, err := AuthenticateToken(ctx, repo, utils.GetTokenFromRequest(r))
 	if err != nil {
 		return nil
 	}
 
-	user, err := repo.GetUserByID(ctx, userIDUUID)
+	return &user.ID
+}
+
+// ErrAccountSuspended is returned when a suspended user tries to sign in or use their token
+var ErrAccountSuspended = errors.New("account is suspended")
+
+// AuthenticateToken validates an access token and the session it was issued for,
+// returning the token's user and session ID. Tokens from revoked sessions or of suspended users are rejected.
+func AuthenticateToken(ctx context.Context, repo *sqlc.Queries, token string) (sqlc.User, pgtype.UUID, error) {
+	claims, err := utils.ParseToken(token, utils.TokenTypeAccess)
 	if err != nil {
-		return nil
+		return sqlc.User{}, pgtype.UUID{}, err
 	}
 
-	return &user.ID
+	sessionID, err := utils.StrToUUID(claims.SessionID)
+	if err != nil {
+		return sqlc.User{}, pgtype.UUID{}, fmt.Errorf("invalid session in token")
+	}
+	session, err := repo.GetSessionByID(ctx, sessionID)
+	if err != nil || session.RevokedAt.Valid || session.UserID.String() != claims.UserID {
+		return sqlc.User{}, pgtype.UUID{}, fmt.Errorf("session is no longer valid")
+	}
+
+	user, err := repo.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		return sqlc.User{}, pgtype.UUID{}, fmt.Errorf("failed to get user from database: %s", err.Error())
+	}
+	if user.SuspendedAt.Valid {
+		return sqlc.User{}, pgtype.UUID{}, ErrAccountSuspended
+	}
+
+	if err := repo.TouchSession(ctx, session.ID); err != nil {
+		return sqlc.User{}, pgtype.UUID{}, fmt.Errorf("failed to update session: %s", err.Error())
+	}
+	return user, session.ID, nil
+}
+
+// GetPaginationParams reads the page and limit query params and returns page, limit and offset
+func GetPaginationParams(r *http.Request) (int, int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+	return page, limit, offset
+}
+
+// IsValidFilter reports whether a listing filter value is empty (no filter) or one of allowed
+func IsValidFilter(value string, allowed ...string) bool {
+	if value == "" {
+		return true
+	}
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
 }
 
 func ExecTx(ctx context.Context, pool *pgxpool.Pool, fn func(*sqlc.Queries) error) error {
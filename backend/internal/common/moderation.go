@@ -0,0 +1,124 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+// Moderation actions recorded in moderation_actions
+const (
+	ModerationActionDelete     = "delete"
+	ModerationActionHide       = "hide"
+	ModerationActionUnhide     = "unhide"
+	ModerationActionChangeRole = "change_role"
+	ModerationActionSuspend    = "suspend"
+	ModerationActionUnsuspend  = "unsuspend"
+)
+
+// Moderation targets recorded in moderation_actions
+const (
+	ModerationTargetPost    = "post"
+	ModerationTargetComment = "comment"
+	ModerationTargetUser    = "user"
+)
+
+// RecordModerationAction writes an entry to the moderation log. Pass the transaction's
+// queries so the entry is only kept if the action itself succeeds.
+func RecordModerationAction(ctx context.Context, q *sqlc.Queries, moderatorID pgtype.UUID, action, targetType string, targetID pgtype.UUID, reason string) error {
+	err := q.CreateModerationAction(ctx, sqlc.CreateModerationActionParams{
+		ModeratorID: moderatorID,
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Reason:      reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record moderation action: %s", err.Error())
+	}
+	return nil
+}
+
+// SetPostHidden hides or unhides a post and records the action. It runs on q so callers can
+// make it part of a larger transaction. Returns pgx.ErrNoRows when the post does not exist.
+func SetPostHidden(ctx context.Context, q *sqlc.Queries, moderatorID, postID pgtype.UUID, hidden bool, reason string) (sqlc.Post, error) {
+	post, err := q.SetPostHidden(ctx, sqlc.SetPostHiddenParams{
+		ID:       postID,
+		IsHidden: hidden,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Post{}, err
+	}
+	if err != nil {
+		return sqlc.Post{}, fmt.Errorf("failed to update post: %s", err.Error())
+	}
+
+	action := ModerationActionUnhide
+	if hidden {
+		action = ModerationActionHide
+	}
+	return post, RecordModerationAction(ctx, q, moderatorID, action, ModerationTargetPost, post.ID, reason)
+}
+
+// SetCommentHidden hides or unhides a comment and records the action. It runs on q so callers
+// can make it part of a larger transaction. Returns pgx.ErrNoRows when the comment does not exist.
+func SetCommentHidden(ctx context.Context, q *sqlc.Queries, moderatorID, commentID pgtype.UUID, hidden bool, reason string) (sqlc.Comment, error) {
+	comment, err := q.SetCommentHidden(ctx, sqlc.SetCommentHiddenParams{
+		ID:       commentID,
+		IsHidden: hidden,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Comment{}, err
+	}
+	if err != nil {
+		return sqlc.Comment{}, fmt.Errorf("failed to update comment: %s", err.Error())
+	}
+
+	action := ModerationActionUnhide
+	if hidden {
+		action = ModerationActionHide
+	}
+	return comment, RecordModerationAction(ctx, q, moderatorID, action, ModerationTargetComment, comment.ID, reason)
+}
+
+// SetUserSuspended suspends a user, signing them out everywhere, or lifts their suspension,
+// and records the action. It runs on q so callers can make it part of a larger transaction.
+// Callers check that the moderator outranks the user.
+func SetUserSuspended(ctx context.Context, q *sqlc.Queries, moderatorID, userID pgtype.UUID, suspended bool, reason string) (sqlc.User, error) {
+	if !suspended {
+		user, err := q.UnsuspendUser(ctx, userID)
+		if err != nil {
+			return sqlc.User{}, fmt.Errorf("failed to unsuspend user: %s", err.Error())
+		}
+		return user, RecordModerationAction(ctx, q, moderatorID, ModerationActionUnsuspend, ModerationTargetUser, userID, reason)
+	}
+
+	user, err := q.SuspendUser(ctx, userID)
+	if err != nil {
+		return sqlc.User{}, fmt.Errorf("failed to suspend user: %s", err.Error())
+	}
+	if err := q.RevokeSessionsByUserID(ctx, userID); err != nil {
+		return sqlc.User{}, fmt.Errorf("failed to revoke sessions: %s", err.Error())
+	}
+	return user, RecordModerationAction(ctx, q, moderatorID, ModerationActionSuspend, ModerationTargetUser, userID, reason)
+}
+
+// ReadModerationReason reads the optional {"reason"} body sent with moderation requests.
+// An empty body means no reason was given.
+func ReadModerationReason(r *http.Request) (string, error) {
+	var payload ModerationRequest
+	if err := utils.ParseJSON(r, &payload); err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("invalid request payload: %s", err.Error())
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return "", err
+	}
+	return payload.Reason, nil
+}
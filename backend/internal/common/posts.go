@@ -3,6 +3,7 @@ package common
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -11,6 +12,15 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// CanViewPost reports whether a post is visible to the requesting user.
+// Unpublished posts and posts hidden by a moderator are only visible to their author.
+func CanViewPost(post sqlc.Post, requestingUserID *pgtype.UUID) bool {
+	if post.IsPublished && !post.IsHidden {
+		return true
+	}
+	return requestingUserID != nil && requestingUserID.Valid && *requestingUserID == post.AuthorID
+}
+
 // EnrichPostsWithDetails fetches and attaches categories, likes, comments, and user-liked status to posts
 func EnrichPostsWithDetails(ctx context.Context, repo *sqlc.Queries, posts []sqlc.Post, requestingUserID *pgtype.UUID) ([]PostCardDTO, error) {
 	if len(posts) == 0 {
@@ -155,23 +165,16 @@ func EnrichPostsWithDetails(ctx context.Context, repo *sqlc.Queries, posts []sql
 		author := authorMap[authorIDStr]
 
 		result[i] = PostCardDTO{
-			ID:          postIDStr,
-			AuthorID:    authorIDStr,
-			Title:       post.Title,
-			Slug:        post.Slug,
-			Body:        post.Body,
-			IsPublished: post.IsPublished,
-			CreatedAt:   post.CreatedAt.Time,
-			UpdatedAt:   post.UpdatedAt.Time,
-			Author: AuthorDTO{
-				ID:        author.ID.String(),
-				GoogleID:  author.GoogleID,
-				Username:  author.Username.String,
-				Email:     author.Email,
-				AvatarURL: author.AvatarUrl.String,
-				CreatedAt: author.CreatedAt.Time,
-				UpdatedAt: author.UpdatedAt.Time,
-			},
+			ID:           postIDStr,
+			AuthorID:     authorIDStr,
+			Title:        post.Title,
+			Slug:         post.Slug,
+			Body:         post.Body,
+			IsPublished:  post.IsPublished,
+			IsHidden:     post.IsHidden,
+			CreatedAt:    post.CreatedAt.Time,
+			UpdatedAt:    post.UpdatedAt.Time,
+			Author:       NewAuthorDTO(author),
 			Categories:   categoryMap[postIDStr],
 			LikeCount:    likeCountMap[postIDStr],
 			CommentCount: commentCountMap[postIDStr],
@@ -181,21 +184,36 @@ func EnrichPostsWithDetails(ctx context.Context, repo *sqlc.Queries, posts []sql
 		if result[i].Categories == nil {
 			result[i].Categories = []CategoryDTO{}
 		}
+
+		if post.PublishAt.Valid {
+			publishAt := post.PublishAt.Time
+			result[i].PublishAt = &publishAt
+		}
+		if post.PublishedAt.Valid {
+			publishedAt := post.PublishedAt.Time
+			result[i].PublishedAt = &publishedAt
+		}
 	}
 
 	return result, nil
 }
 
-func EnrichCommentsWithAuthors(ctx context.Context, repo *sqlc.Queries, comments []sqlc.Comment) ([]CommentDTO, error) {
+// EnrichCommentsWithAuthors fetches and attaches authors, like counts, and user-liked status to comments
+func EnrichCommentsWithAuthors(ctx context.Context, repo *sqlc.Queries, comments []sqlc.Comment, requestingUserID *pgtype.UUID) ([]CommentDTO, error) {
 	if len(comments) == 0 {
 		return []CommentDTO{}, nil
 	}
 
-	// extract unique author IDs
+	// extract comment IDs and unique author IDs
+	commentIDs := make([]pgtype.UUID, len(comments))
 	authorIDmap := make(map[string]bool)
 
-	for _, comment := range comments {
-		authorIDmap[comment.UserID.String()] = true
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+		// placeholders left by a purged account have no author
+		if comment.UserID.Valid {
+			authorIDmap[comment.UserID.String()] = true
+		}
 	}
 
 	authorIDs := make([]pgtype.UUID, 0, len(authorIDmap))
@@ -204,40 +222,148 @@ func EnrichCommentsWithAuthors(ctx context.Context, repo *sqlc.Queries, comments
 		authorIDs = append(authorIDs, id)
 	}
 
-	// fetch authors
-	authors, err := repo.GetUsersByIDs(ctx, authorIDs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get authors: %w", err)
+	// fetch all data in parallel
+	var (
+		authors             []sqlc.User
+		likeCounts          []sqlc.GetLikeCountsByCommentIDsRow
+		userLikedCommentIDs []pgtype.UUID
+		mu                  sync.Mutex
+	)
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	// Fetch authors
+	g.Go(func() error {
+		result, err := repo.GetUsersByIDs(gCtx, authorIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get authors: %w", err)
+		}
+		mu.Lock()
+		authors = result
+		mu.Unlock()
+		return nil
+	})
+
+	// Fetch like counts
+	g.Go(func() error {
+		result, err := repo.GetLikeCountsByCommentIDs(gCtx, commentIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get comment like counts: %w", err)
+		}
+		mu.Lock()
+		likeCounts = result
+		mu.Unlock()
+		return nil
+	})
+
+	// Fetch user liked comment IDs if authenticated
+	if requestingUserID != nil && requestingUserID.Valid {
+		g.Go(func() error {
+			result, err := repo.GetUserLikedCommentIDs(gCtx, sqlc.GetUserLikedCommentIDsParams{
+				UserID:  *requestingUserID,
+				Column2: commentIDs,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get user liked comment IDs: %w", err)
+			}
+			mu.Lock()
+			userLikedCommentIDs = result
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	// wait for all fetches to complete
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
-	// build author lookup map
+	// build lookup maps
 	authorMap := make(map[string]sqlc.User)
 	for _, author := range authors {
 		authorMap[author.ID.String()] = author
 	}
 
+	likeCountMap := make(map[string]int64)
+	for _, likeCount := range likeCounts {
+		likeCountMap[likeCount.CommentID.String()] = likeCount.LikeCount
+	}
+
+	userLikedCommentIDMap := make(map[string]bool)
+	for _, likedCommentID := range userLikedCommentIDs {
+		userLikedCommentIDMap[likedCommentID.String()] = true
+	}
+
 	// assemble final DTOs
 	result := make([]CommentDTO, len(comments))
 	for i, comment := range comments {
+		commentIDStr := comment.ID.String()
 		authorIDStr := comment.UserID.String()
 		author := authorMap[authorIDStr]
 		result[i] = CommentDTO{
-			ID:        comment.ID.String(),
-			PostID:    comment.PostID.String(),
-			UserID:    authorIDStr,
-			Body:      comment.Body,
-			CreatedAt: comment.CreatedAt.Time,
-			UpdatedAt: comment.UpdatedAt.Time,
-			Author: AuthorDTO{
-				ID:        author.ID.String(),
-				GoogleID:  author.GoogleID,
-				Username:  author.Username.String,
-				Email:     author.Email,
-				AvatarURL: author.AvatarUrl.String,
-				CreatedAt: author.CreatedAt.Time,
-				UpdatedAt: author.UpdatedAt.Time,
-			},
+			ID:           commentIDStr,
+			PostID:       comment.PostID.String(),
+			UserID:       authorIDStr,
+			Body:         comment.Body,
+			IsDeleted:    comment.IsDeleted,
+			IsHidden:     comment.IsHidden,
+			CreatedAt:    comment.CreatedAt.Time,
+			UpdatedAt:    comment.UpdatedAt.Time,
+			Author:       NewAuthorDTO(author),
+			LikeCount:    likeCountMap[commentIDStr],
+			UserHasLiked: userLikedCommentIDMap[commentIDStr],
+			Replies:      []CommentDTO{},
+		}
+
+		if comment.ParentID.Valid {
+			parentID := comment.ParentID.String()
+			result[i].ParentID = &parentID
+		}
+
+		// deleted placeholders keep their position in the thread but hide who wrote them,
+		// and comments hidden by a moderator are shown the same way
+		if comment.IsDeleted {
+			result[i].UserID = ""
+			result[i].Author = AuthorDTO{}
+		}
+		if comment.IsHidden {
+			result[i].Body = ""
+			result[i].UserID = ""
+			result[i].Author = AuthorDTO{}
 		}
 	}
 	return result, nil
 }
+
+// BuildCommentTree nests replies under their parent comments. Top-level comments keep
+// their input order, while replies are ordered oldest first so conversations read naturally.
+func BuildCommentTree(comments []CommentDTO) []CommentDTO {
+	childrenMap := make(map[string][]CommentDTO)
+	roots := make([]CommentDTO, 0)
+
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		childrenMap[*comment.ParentID] = append(childrenMap[*comment.ParentID], comment)
+	}
+
+	var attach func(comment CommentDTO) CommentDTO
+	attach = func(comment CommentDTO) CommentDTO {
+		children := childrenMap[comment.ID]
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].CreatedAt.Before(children[j].CreatedAt)
+		})
+		comment.Replies = make([]CommentDTO, len(children))
+		for i, child := range children {
+			comment.Replies[i] = attach(child)
+		}
+		return comment
+	}
+
+	for i, root := range roots {
+		roots[i] = attach(root)
+	}
+	return roots
+}
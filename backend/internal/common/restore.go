@@ -0,0 +1,18 @@
+package common
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/config"
+)
+
+// RestoreWindow is how long deleted posts, comments and accounts can be restored before they are purged
+func RestoreWindow() time.Duration {
+	return time.Duration(config.Envs.RESTORE_WINDOW_DAYS) * 24 * time.Hour
+}
+
+// RestoreCutoff returns the earliest deletion time that can still be restored
+func RestoreCutoff() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now().Add(-RestoreWindow()), Valid: true}
+}
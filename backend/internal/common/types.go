@@ -14,13 +14,42 @@ type CategoryDTO struct {
 
 // AuthorDTO represents author information in post responses
 type AuthorDTO struct {
-	ID        string    `json:"id"`
-	GoogleID  string    `json:"google_id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	AvatarURL string    `json:"avatar_url"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url"`
+	WebsiteURL  string    `json:"website_url"`
+	GithubURL   string    `json:"github_url"`
+	TwitterURL  string    `json:"twitter_url"`
+	LinkedinURL string    `json:"linkedin_url"`
+	Location    string    `json:"location"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PublicUserDTO is a user as anyone may see them. Email is only included when the user opted in.
+type PublicUserDTO struct {
+	ID          string    `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Email       string    `json:"email,omitempty"`
+	Description string    `json:"description"`
+	AvatarURL   string    `json:"avatar_url"`
+	WebsiteURL  string    `json:"website_url"`
+	GithubURL   string    `json:"github_url"`
+	TwitterURL  string    `json:"twitter_url"`
+	LinkedinURL string    `json:"linkedin_url"`
+	Location    string    `json:"location"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PrivateUserDTO is the signed-in user's own account, including their email and settings
+type PrivateUserDTO struct {
+	PublicUserDTO
+	ShowEmail       bool      `json:"show_email"`
+	CustomAvatarURL string    `json:"custom_avatar_url"`
+	Role            string    `json:"role"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // PostCardDTO represents the structure of a post card
@@ -31,6 +60,9 @@ type PostCardDTO struct {
 	Slug         string        `json:"slug"`
 	Body         string        `json:"body"`
 	IsPublished  bool          `json:"is_published"`
+	IsHidden     bool          `json:"is_hidden"`
+	PublishAt    *time.Time    `json:"publish_at"`
+	PublishedAt  *time.Time    `json:"published_at"`
 	CreatedAt    time.Time     `json:"created_at"`
 	UpdatedAt    time.Time     `json:"updated_at"`
 	Author       AuthorDTO     `json:"author"`
@@ -40,12 +72,40 @@ type PostCardDTO struct {
 	UserHasLiked bool          `json:"user_has_liked"`
 }
 
+// CommentDTO represents a comment and, when returned as a thread, its replies
 type CommentDTO struct {
-	ID        string    `json:"id"`
-	PostID    string    `json:"post_id"`
-	UserID    string    `json:"user_id"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Author    AuthorDTO `json:"author"`
+	ID           string       `json:"id"`
+	PostID       string       `json:"post_id"`
+	UserID       string       `json:"user_id"`
+	ParentID     *string      `json:"parent_id"`
+	Body         string       `json:"body"`
+	IsDeleted    bool         `json:"is_deleted"`
+	IsHidden     bool         `json:"is_hidden"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	Author       AuthorDTO    `json:"author"`
+	LikeCount    int64        `json:"like_count"`
+	UserHasLiked bool         `json:"user_has_liked"`
+	Replies      []CommentDTO `json:"replies"`
+}
+
+// ModerationRequest is the optional body of a moderation request
+type ModerationRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// CursorPaginatedPosts is the response envelope for cursor-paginated post listings
+type CursorPaginatedPosts struct {
+	Posts      []PostCardDTO `json:"posts"`
+	NextCursor string        `json:"next_cursor"`
+	HasMore    bool          `json:"hasMore"`
+}
+
+// PaginatedPosts is the response envelope for page-numbered post listings
+type PaginatedPosts struct {
+	Posts   []PostCardDTO `json:"posts"`
+	Page    int           `json:"page"`
+	Limit   int           `json:"limit"`
+	Total   int64         `json:"total"`
+	HasMore bool          `json:"hasMore"`
 }
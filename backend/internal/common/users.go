@@ -0,0 +1,70 @@
+package common
+
+import "github.com/neevan0842/BlogSphere/backend/database/sqlc"
+
+// NewAuthorDTO converts a user to the author shown on post cards and comments
+func NewAuthorDTO(author sqlc.User) AuthorDTO {
+	return AuthorDTO{
+		ID:          author.ID.String(),
+		Username:    author.Username.String,
+		DisplayName: author.DisplayName.String,
+		AvatarURL:   avatarURL(author),
+		WebsiteURL:  author.WebsiteUrl.String,
+		GithubURL:   author.GithubUrl.String,
+		TwitterURL:  author.TwitterUrl.String,
+		LinkedinURL: author.LinkedinUrl.String,
+		Location:    author.Location.String,
+		CreatedAt:   author.CreatedAt.Time,
+		UpdatedAt:   author.UpdatedAt.Time,
+	}
+}
+
+// NewPublicUserDTO converts a user for responses that anyone can read
+func NewPublicUserDTO(user sqlc.User) PublicUserDTO {
+	dto := PublicUserDTO{
+		ID:          user.ID.String(),
+		Username:    user.Username.String,
+		DisplayName: user.DisplayName.String,
+		Description: user.Description.String,
+		AvatarURL:   avatarURL(user),
+		WebsiteURL:  user.WebsiteUrl.String,
+		GithubURL:   user.GithubUrl.String,
+		TwitterURL:  user.TwitterUrl.String,
+		LinkedinURL: user.LinkedinUrl.String,
+		Location:    user.Location.String,
+		CreatedAt:   user.CreatedAt.Time,
+	}
+	if user.ShowEmail {
+		dto.Email = user.Email
+	}
+	return dto
+}
+
+func NewPublicUserDTOs(users []sqlc.User) []PublicUserDTO {
+	result := make([]PublicUserDTO, len(users))
+	for i, user := range users {
+		result[i] = NewPublicUserDTO(user)
+	}
+	return result
+}
+
+// NewPrivateUserDTO converts a user for responses that only they can read
+func NewPrivateUserDTO(user sqlc.User) PrivateUserDTO {
+	dto := PrivateUserDTO{
+		PublicUserDTO:   NewPublicUserDTO(user),
+		ShowEmail:       user.ShowEmail,
+		CustomAvatarURL: user.CustomAvatarUrl.String,
+		Role:            user.Role,
+		UpdatedAt:       user.UpdatedAt.Time,
+	}
+	dto.Email = user.Email
+	return dto
+}
+
+// avatarURL prefers the user's custom avatar over the one from their login provider
+func avatarURL(user sqlc.User) string {
+	if user.CustomAvatarUrl.Valid {
+		return user.CustomAvatarUrl.String
+	}
+	return user.AvatarUrl.String
+}
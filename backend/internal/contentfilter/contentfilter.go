@@ -0,0 +1,142 @@
+package contentfilter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/config"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+)
+
+// Action is what the filter decided to do with a post or comment
+type Action string
+
+// Actions, from least to most strict
+const (
+	ActionAllow  Action = "allow"
+	ActionHold   Action = "hold"
+	ActionReject Action = "reject"
+)
+
+// Verdict statuses. Verdicts start pending until a moderator approves the content or confirms the decision.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusConfirmed = "confirmed"
+)
+
+// ErrRejected is returned when a post or comment is rejected by the filter
+var ErrRejected = errors.New("content was rejected by the content filter")
+
+// Content is a post or comment about to be saved
+type Content struct {
+	TargetType string      // common.ModerationTargetPost or common.ModerationTargetComment
+	TargetID   pgtype.UUID // set when the content is an edit of an existing post or comment
+	AuthorID   pgtype.UUID
+	Text       string
+}
+
+// Verdict is a filter's decision along with the reasons for it
+type Verdict struct {
+	Action  Action
+	Reasons []string
+}
+
+func allow() Verdict {
+	return Verdict{Action: ActionAllow}
+}
+
+// Filter checks a single rule against content
+type Filter interface {
+	Check(ctx context.Context, content Content) (Verdict, error)
+}
+
+// Pipeline runs content through its filters. The strictest verdict wins and the
+// reasons of every filter that did not allow the content are kept.
+type Pipeline struct {
+	repo    *sqlc.Queries
+	filters []Filter
+}
+
+func NewPipeline(repo *sqlc.Queries, filters ...Filter) *Pipeline {
+	return &Pipeline{
+		repo:    repo,
+		filters: filters,
+	}
+}
+
+// NewDefaultPipeline builds the filters from the content filter configuration. When the
+// filter is disabled the pipeline has no filters and allows everything.
+func NewDefaultPipeline(repo *sqlc.Queries, duplicateWindow time.Duration) *Pipeline {
+	if !config.Envs.CONTENT_FILTER_ENABLED {
+		return NewPipeline(repo)
+	}
+	return NewPipeline(repo,
+		NewWordListFilter(splitList(config.Envs.CONTENT_FILTER_BLOCKED_WORDS), splitList(config.Envs.CONTENT_FILTER_FLAGGED_WORDS)),
+		NewLinkCountFilter(int(config.Envs.CONTENT_FILTER_MAX_LINKS)),
+		NewDuplicateFilter(repo, duplicateWindow, int(config.Envs.CONTENT_FILTER_DUPLICATE_MIN_LENGTH)),
+		NewFirstPostLinkFilter(repo),
+	)
+}
+
+// Check runs the content through every filter. Rejections are recorded for moderators here
+// and returned as ErrRejected; held content must be recorded with Record once it is saved.
+func (p *Pipeline) Check(ctx context.Context, content Content) (Verdict, error) {
+	result := allow()
+	for _, filter := range p.filters {
+		verdict, err := filter.Check(ctx, content)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if verdict.Action == ActionAllow {
+			continue
+		}
+		if verdict.Action == ActionReject || result.Action == ActionAllow {
+			result.Action = verdict.Action
+		}
+		result.Reasons = append(result.Reasons, verdict.Reasons...)
+	}
+
+	if result.Action == ActionReject {
+		if err := Record(ctx, p.repo, content, pgtype.UUID{}, result); err != nil {
+			return Verdict{}, err
+		}
+		return result, fmt.Errorf("%w: %s", ErrRejected, strings.Join(result.Reasons, "; "))
+	}
+	return result, nil
+}
+
+// Record stores a hold or reject verdict for moderator review. targetID is the saved post or
+// comment, if any. Content the filter allowed is not recorded.
+func Record(ctx context.Context, q *sqlc.Queries, content Content, targetID pgtype.UUID, verdict Verdict) error {
+	if verdict.Action == ActionAllow {
+		return nil
+	}
+	_, err := q.CreateContentFilterVerdict(ctx, sqlc.CreateContentFilterVerdictParams{
+		AuthorID:   content.AuthorID,
+		TargetType: content.TargetType,
+		TargetID:   targetID,
+		Action:     string(verdict.Action),
+		Reasons:    verdict.Reasons,
+		Body:       content.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record content filter verdict: %s", err.Error())
+	}
+	return nil
+}
+
+// splitList parses a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,149 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+)
+
+// linkPattern matches web links, with or without a scheme
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://\S+|\bwww\.\S+`)
+
+// WordListFilter rejects content containing a blocked word and holds content containing a
+// flagged one. Words match case-insensitively and only as whole words or phrases.
+type WordListFilter struct {
+	blocked *regexp.Regexp
+	flagged *regexp.Regexp
+}
+
+func NewWordListFilter(blockedWords, flaggedWords []string) *WordListFilter {
+	return &WordListFilter{
+		blocked: wordPattern(blockedWords),
+		flagged: wordPattern(flaggedWords),
+	}
+}
+
+func (f *WordListFilter) Check(ctx context.Context, content Content) (Verdict, error) {
+	if f.blocked != nil {
+		if word := f.blocked.FindString(content.Text); word != "" {
+			return Verdict{Action: ActionReject, Reasons: []string{fmt.Sprintf("contains blocked word %q", strings.ToLower(word))}}, nil
+		}
+	}
+	if f.flagged != nil {
+		if word := f.flagged.FindString(content.Text); word != "" {
+			return Verdict{Action: ActionHold, Reasons: []string{fmt.Sprintf("contains flagged word %q", strings.ToLower(word))}}, nil
+		}
+	}
+	return allow(), nil
+}
+
+// wordPattern compiles a case-insensitive whole-word pattern for words, or nil when there are none
+func wordPattern(words []string) *regexp.Regexp {
+	if len(words) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// LinkCountFilter holds content with more than maxLinks links
+type LinkCountFilter struct {
+	maxLinks int
+}
+
+func NewLinkCountFilter(maxLinks int) *LinkCountFilter {
+	return &LinkCountFilter{maxLinks: maxLinks}
+}
+
+func (f *LinkCountFilter) Check(ctx context.Context, content Content) (Verdict, error) {
+	if count := len(linkPattern.FindAllString(content.Text, -1)); count > f.maxLinks {
+		return Verdict{Action: ActionHold, Reasons: []string{fmt.Sprintf("contains %d links, more than the %d allowed", count, f.maxLinks)}}, nil
+	}
+	return allow(), nil
+}
+
+// DuplicateFilter rejects a comment repeating one its author posted within the window and holds
+// one matching another user's, so spam spread across several accounts is caught. Bodies shorter
+// than minLength are only checked against the author's own comments, since different people
+// often post the same short reply. Posts are not checked.
+type DuplicateFilter struct {
+	repo      *sqlc.Queries
+	window    time.Duration
+	minLength int
+}
+
+func NewDuplicateFilter(repo *sqlc.Queries, window time.Duration, minLength int) *DuplicateFilter {
+	return &DuplicateFilter{
+		repo:      repo,
+		window:    window,
+		minLength: minLength,
+	}
+}
+
+func (f *DuplicateFilter) Check(ctx context.Context, content Content) (Verdict, error) {
+	if content.TargetType != common.ModerationTargetComment {
+		return allow(), nil
+	}
+	body := normalizeBody(content.Text)
+	counts, err := f.repo.CountRecentDuplicateComments(ctx, sqlc.CountRecentDuplicateCommentsParams{
+		AuthorID:     content.AuthorID,
+		Body:         body,
+		CreatedAfter: pgtype.Timestamptz{Time: time.Now().Add(-f.window), Valid: true},
+		ExcludeID:    content.TargetID,
+	})
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to check for duplicate comments: %s", err.Error())
+	}
+	return duplicateVerdict(body, counts.SameAuthorCount, counts.OtherAuthorCount, f.minLength), nil
+}
+
+// duplicateVerdict decides on a normalized comment body given how many recent comments by its
+// author and by other users have the same body
+func duplicateVerdict(body string, sameAuthorCount, otherAuthorCount int64, minLength int) Verdict {
+	if sameAuthorCount > 0 {
+		return Verdict{Action: ActionReject, Reasons: []string{"duplicates one of your recent comments"}}
+	}
+	if otherAuthorCount > 0 && utf8.RuneCountInString(body) >= minLength {
+		return Verdict{Action: ActionHold, Reasons: []string{"matches a recent comment by another user"}}
+	}
+	return allow()
+}
+
+// normalizeBody lowercases text and collapses its whitespace, the way CountRecentDuplicateComments compares bodies
+func normalizeBody(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// FirstPostLinkFilter holds content with links from authors who have nothing visible on the
+// site yet, since throwaway spam accounts usually lead with a link
+type FirstPostLinkFilter struct {
+	repo *sqlc.Queries
+}
+
+func NewFirstPostLinkFilter(repo *sqlc.Queries) *FirstPostLinkFilter {
+	return &FirstPostLinkFilter{repo: repo}
+}
+
+func (f *FirstPostLinkFilter) Check(ctx context.Context, content Content) (Verdict, error) {
+	if !linkPattern.MatchString(content.Text) {
+		return allow(), nil
+	}
+	count, err := f.repo.CountVisibleContentByUserID(ctx, content.AuthorID)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to count author's content: %s", err.Error())
+	}
+	if count == 0 {
+		return Verdict{Action: ActionHold, Reasons: []string{"first-time poster included a link"}}, nil
+	}
+	return allow(), nil
+}
@@ -0,0 +1,256 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
+)
+
+func TestWordListFilter(t *testing.T) {
+	filter := NewWordListFilter([]string{"scam"}, []string{"free money", "casino"})
+	tests := []struct {
+		text string
+		want Action
+	}{
+		{"a perfectly normal comment", ActionAllow},
+		{"this is a SCAM", ActionReject},
+		{"scammer", ActionAllow},
+		{"get free money now", ActionHold},
+		{"casino scam", ActionReject},
+	}
+	for _, tt := range tests {
+		verdict, err := filter.Check(context.Background(), Content{Text: tt.text})
+		if err != nil {
+			t.Fatalf("Check(%q): %v", tt.text, err)
+		}
+		if verdict.Action != tt.want {
+			t.Errorf("Check(%q) = %s, want %s", tt.text, verdict.Action, tt.want)
+		}
+	}
+}
+
+func TestLinkCountFilter(t *testing.T) {
+	filter := NewLinkCountFilter(2)
+	tests := []struct {
+		text string
+		want Action
+	}{
+		{"no links here", ActionAllow},
+		{"see https://example.com and www.example.org", ActionAllow},
+		{"http://a.example http://b.example www.c.example", ActionHold},
+	}
+	for _, tt := range tests {
+		verdict, err := filter.Check(context.Background(), Content{Text: tt.text})
+		if err != nil {
+			t.Fatalf("Check(%q): %v", tt.text, err)
+		}
+		if verdict.Action != tt.want {
+			t.Errorf("Check(%q) = %s, want %s", tt.text, verdict.Action, tt.want)
+		}
+	}
+}
+
+func TestDuplicateVerdict(t *testing.T) {
+	long := "check out my channel for daily giveaways"
+	tests := []struct {
+		name             string
+		body             string
+		sameAuthorCount  int64
+		otherAuthorCount int64
+		want             Action
+	}{
+		{"no duplicates", long, 0, 0, ActionAllow},
+		{"repeats own comment", long, 1, 0, ActionReject},
+		{"repeats own short comment", "thanks!", 1, 3, ActionReject},
+		{"matches another user", long, 0, 1, ActionHold},
+		{"short match with other users", "thanks!", 0, 5, ActionAllow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := duplicateVerdict(tt.body, tt.sameAuthorCount, tt.otherAuthorCount, 30)
+			if got.Action != tt.want {
+				t.Errorf("duplicateVerdict = %s, want %s", got.Action, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBody(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello World", "hello world"},
+		{"  hello \n\t world  ", "hello world"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeBody(tt.text); got != tt.want {
+			t.Errorf("normalizeBody(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// stubFilter returns a fixed verdict
+type stubFilter Verdict
+
+func (f stubFilter) Check(ctx context.Context, content Content) (Verdict, error) {
+	return Verdict(f), nil
+}
+
+func TestPipelineKeepsStrictestVerdict(t *testing.T) {
+	hold := stubFilter{Action: ActionHold, Reasons: []string{"held"}}
+	tests := []struct {
+		name        string
+		filters     []Filter
+		wantAction  Action
+		wantReasons int
+	}{
+		{"no filters", nil, ActionAllow, 0},
+		{"all allow", []Filter{stubFilter(allow()), stubFilter(allow())}, ActionAllow, 0},
+		{"one hold", []Filter{stubFilter(allow()), hold}, ActionHold, 1},
+		{"two holds", []Filter{hold, hold}, ActionHold, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := NewPipeline(nil, tt.filters...).Check(context.Background(), Content{})
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if verdict.Action != tt.wantAction || len(verdict.Reasons) != tt.wantReasons {
+				t.Errorf("Check = %s with %d reasons, want %s with %d", verdict.Action, len(verdict.Reasons), tt.wantAction, tt.wantReasons)
+			}
+		})
+	}
+}
+
+// newTestQueries returns queries backed by a fresh schema in the database at
+// TEST_DATABASE_URL with every migration applied. Tests are skipped without one.
+func newTestQueries(t *testing.T) *sqlc.Queries {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer admin.Close(ctx)
+
+	schema := fmt.Sprintf("contentfilter_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(context.Background(), databaseURL)
+		if err != nil {
+			return
+		}
+		defer conn.Close(context.Background())
+		conn.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		t.Fatalf("parse database URL: %v", err)
+	}
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrations, err := filepath.Glob("../../database/migrations/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("no migrations found: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		contents, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("read %s: %v", migration, err)
+		}
+		if _, err := pool.Exec(ctx, string(contents)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(migration), err)
+		}
+	}
+
+	return sqlc.New(pool)
+}
+
+func createTestUser(t *testing.T, q *sqlc.Queries, username string) pgtype.UUID {
+	t.Helper()
+	user, err := q.CreateUser(context.Background(), sqlc.CreateUserParams{
+		Username: pgtype.Text{String: username, Valid: true},
+		Email:    username + "@example.com",
+	})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user.ID
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	q := newTestQueries(t)
+	ctx := context.Background()
+	author := createTestUser(t, q, "author")
+	other := createTestUser(t, q, "other")
+	post, err := q.CreatePost(ctx, sqlc.CreatePostParams{
+		Title:       "A post",
+		Body:        "body",
+		Slug:        "a-post",
+		AuthorID:    author,
+		IsPublished: true,
+	})
+	if err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	for _, body := range []string{"Check out my channel for daily giveaways", "thanks!"} {
+		if _, err := q.CreateComment(ctx, sqlc.CreateCommentParams{PostID: post.ID, UserID: other, Body: body}); err != nil {
+			t.Fatalf("create comment: %v", err)
+		}
+	}
+
+	filter := NewDuplicateFilter(q, time.Hour, 30)
+	tests := []struct {
+		name     string
+		authorID pgtype.UUID
+		text     string
+		want     Action
+	}{
+		{"new comment", author, "something nobody said before", ActionAllow},
+		{"another user's long comment", author, "check out  my channel for DAILY giveaways", ActionHold},
+		{"another user's short comment", author, "Thanks!", ActionAllow},
+		{"own long comment", other, "check out my channel for daily giveaways", ActionReject},
+		{"own short comment", other, "thanks!", ActionReject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := filter.Check(ctx, Content{
+				TargetType: common.ModerationTargetComment,
+				AuthorID:   tt.authorID,
+				Text:       tt.text,
+			})
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if verdict.Action != tt.want {
+				t.Errorf("Check(%q) = %s, want %s", tt.text, verdict.Action, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"github.com/neevan0842/BlogSphere/backend/internal/common"
 	"github.com/neevan0842/BlogSphere/backend/utils"
 	"go.uber.org/zap"
 )
@@ -24,24 +26,36 @@ func NewMiddleware(repo *sqlc.Queries, logger *zap.SugaredLogger) *middleware {
 
 func (m *middleware) UserAuthentication(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := utils.GetTokenFromRequest(r)
-		userIDUUID, err := utils.GetUserIDFromToken(w, token)
-		if err != nil {
-			m.logger.Errorf("invalid userID in token: %s", err.Error())
-			utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
+		// validate the token and its session, then add the user to context
+		user, sessionID, err := common.AuthenticateToken(r.Context(), m.repo, utils.GetTokenFromRequest(r))
+		if errors.Is(err, common.ErrAccountSuspended) {
+			utils.WriteError(w, http.StatusForbidden, err)
 			return
 		}
-
-		// get user from database and add it to context
-		user, err := m.repo.GetUserByID(r.Context(), userIDUUID)
 		if err != nil {
-			m.logger.Errorf("failed to get user from database: %s", err.Error())
+			m.logger.Errorf("failed to authenticate request: %s", err.Error())
 			utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("invalid token"))
 			return
 		}
 
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, utils.UserContextKey, user.ID.String())
+		ctx = context.WithValue(ctx, utils.SessionContextKey, sessionID.String())
+		ctx = context.WithValue(ctx, utils.AuthUserContextKey, user)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// RequireRole only lets through users with at least the given role. It must run after UserAuthentication.
+func (m *middleware) RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := utils.GetUserFromContext(r.Context())
+			if !ok || !utils.HasRole(user.Role, role) {
+				utils.PermissionDenied(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
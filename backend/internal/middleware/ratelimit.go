@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/httprate"
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+// RateLimitByIP allows requestLimit requests per window from each client IP
+func RateLimitByIP(requestLimit int, window time.Duration) func(http.Handler) http.Handler {
+	return httprate.Limit(requestLimit, window,
+		httprate.WithKeyByIP(),
+		httprate.WithLimitHandler(onRateLimited),
+	)
+}
+
+// RateLimitByUser allows requestLimit requests per window from each authenticated user, so a
+// user can't get around it by switching IPs. Requests without a user fall back to the client IP.
+// Every call creates a separate budget, and it must run after UserAuthentication to see the user.
+func RateLimitByUser(requestLimit int, window time.Duration) func(http.Handler) http.Handler {
+	return httprate.Limit(requestLimit, window,
+		httprate.WithKeyFuncs(keyByUser),
+		httprate.WithLimitHandler(onRateLimited),
+	)
+}
+
+func keyByUser(r *http.Request) (string, error) {
+	if userID, ok := utils.GetUserIDFromContext(r.Context()); ok {
+		return "user:" + userID, nil
+	}
+	ip, err := httprate.KeyByIP(r)
+	return "ip:" + ip, err
+}
+
+// onRateLimited responds once a budget is used up. The limiter has already set the
+// Retry-After and X-RateLimit-* headers.
+func onRateLimited(w http.ResponseWriter, r *http.Request) {
+	utils.WriteError(w, http.StatusTooManyRequests, fmt.Errorf("too many requests, please try again later"))
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neevan0842/BlogSphere/backend/utils"
+)
+
+func TestKeyByUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		want   string
+	}{
+		{name: "signed in", userID: "3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b", want: "user:3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b"},
+		{name: "anonymous", want: "ip:192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			if tt.userID != "" {
+				r = r.WithContext(context.WithValue(r.Context(), utils.UserContextKey, tt.userID))
+			}
+			got, err := keyByUser(r)
+			if err != nil {
+				t.Fatalf("keyByUser: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("keyByUser = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitByUser(t *testing.T) {
+	handler := RateLimitByUser(1, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func(remoteAddr, userID string) int {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		if userID != "" {
+			r = r.WithContext(context.WithValue(r.Context(), utils.UserContextKey, userID))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		userID     string
+		want       int
+	}{
+		{"first request from a user", "192.0.2.1:1234", "user-1", http.StatusOK},
+		{"same user from another IP", "192.0.2.2:1234", "user-1", http.StatusTooManyRequests},
+		{"another user from the same IP", "192.0.2.1:1234", "user-2", http.StatusOK},
+		{"anonymous request from a used IP", "192.0.2.1:1234", "", http.StatusOK},
+		{"anonymous request again from that IP", "192.0.2.1:1234", "", http.StatusTooManyRequests},
+		{"anonymous request from another IP", "192.0.2.3:1234", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := request(tt.remoteAddr, tt.userID); got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -7,15 +7,17 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/go-chi/httprate"
 	"github.com/jackc/pgx/v5/pgxpool"
 	envs "github.com/neevan0842/BlogSphere/backend/config"
 	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 	"github.com/neevan0842/BlogSphere/backend/internal/api/auth"
 	"github.com/neevan0842/BlogSphere/backend/internal/api/categories"
 	"github.com/neevan0842/BlogSphere/backend/internal/api/comments"
+	"github.com/neevan0842/BlogSphere/backend/internal/api/moderation"
 	"github.com/neevan0842/BlogSphere/backend/internal/api/posts"
+	"github.com/neevan0842/BlogSphere/backend/internal/api/reports"
 	"github.com/neevan0842/BlogSphere/backend/internal/api/users"
+	"github.com/neevan0842/BlogSphere/backend/internal/contentfilter"
 	mw "github.com/neevan0842/BlogSphere/backend/internal/middleware"
 	"github.com/neevan0842/BlogSphere/backend/mailer"
 	"github.com/neevan0842/BlogSphere/backend/utils"
@@ -30,6 +32,7 @@ type application struct {
 	logger *zap.SugaredLogger
 	db     *pgxpool.Pool
 	mail   *mailer.Mailer
+	filter *contentfilter.Pipeline
 }
 
 type config struct {
@@ -37,7 +40,7 @@ type config struct {
 	dsn  string
 }
 
-func NewAPIServer(addr string, db *pgxpool.Pool, logger *zap.SugaredLogger, mail *mailer.Mailer) *application {
+func NewAPIServer(addr string, db *pgxpool.Pool, logger *zap.SugaredLogger, mail *mailer.Mailer, filter *contentfilter.Pipeline) *application {
 	return &application{
 		config: config{
 			addr: addr,
@@ -46,6 +49,7 @@ func NewAPIServer(addr string, db *pgxpool.Pool, logger *zap.SugaredLogger, mail
 		db:     db,
 		logger: logger,
 		mail:   mail,
+		filter: filter,
 	}
 }
 
@@ -80,8 +84,6 @@ func (app *application) Mount() http.Handler {
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
 
-	// rate limiting middleware
-	r.Use(httprate.LimitByIP(100, time.Minute))
 	// prometheus metrics middleware
 	r.Use(mw.PrometheusMiddleware)
 
@@ -99,19 +101,37 @@ func (app *application) Mount() http.Handler {
 	userService := users.NewService(repo, app.db)
 	userHandler := users.NewHandler(userService, app.logger, repo, app.mail)
 
-	postService := posts.NewService(repo, app.db)
+	postService := posts.NewService(repo, app.db, app.filter)
 	postHandler := posts.NewHandler(postService, app.logger, repo)
 
-	commentService := comments.NewService(repo, app.db)
+	commentService := comments.NewService(repo, app.db, app.filter)
 	commentHandler := comments.NewHandler(commentService, app.logger, repo)
 
 	categoryService := categories.NewService(repo, app.db)
 	categoryHandler := categories.NewHandler(categoryService, app.logger, repo)
 
+	moderationService := moderation.NewService(repo, app.db)
+	moderationHandler := moderation.NewHandler(moderationService, app.logger, repo)
+
+	reportService := reports.NewService(repo, app.db)
+	reportHandler := reports.NewHandler(reportService, app.logger, repo)
+
 	// Initialize middleware
 	authMiddleware := mw.NewMiddleware(repo, app.logger)
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+	// Routes that don't require signing in are limited by IP, and auth endpoints get a stricter IP budget.
+	// Signed-in users share an IP behind NATs and proxies, so their requests draw on a per-user budget
+	// instead, and writes that are easy to abuse get tighter per-user budgets of their own.
+	ipLimit := mw.RateLimitByIP(int(envs.Envs.RATE_LIMIT_IP_PER_MINUTE), time.Minute)
+	authLimit := mw.RateLimitByIP(int(envs.Envs.RATE_LIMIT_AUTH_PER_MINUTE), time.Minute)
+	userLimit := mw.RateLimitByUser(int(envs.Envs.RATE_LIMIT_USER_PER_MINUTE), time.Minute)
+	reportLimit := mw.RateLimitByUser(int(envs.Envs.RATE_LIMIT_REPORTS_PER_HOUR), time.Hour)
+	usernameLimit := mw.RateLimitByUser(int(envs.Envs.RATE_LIMIT_USERNAME_PER_DAY), 24*time.Hour)
+	postLimit := mw.RateLimitByUser(int(envs.Envs.RATE_LIMIT_POSTS_PER_HOUR), time.Hour)
+	commentLimit := mw.RateLimitByUser(int(envs.Envs.RATE_LIMIT_COMMENTS_PER_MINUTE), time.Minute)
+	likeLimit := mw.RateLimitByUser(int(envs.Envs.RATE_LIMIT_LIKES_PER_MINUTE), time.Minute)
+
+	r.With(ipLimit).Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		utils.WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	})
 
@@ -124,56 +144,120 @@ func (app *application) Mount() http.Handler {
 		utils.HttpRequestsTotal,
 		utils.HttpRequestDuration,
 	)
-	r.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	r.With(ipLimit).Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	// public keys for verifying BlogSphere tokens
+	r.With(ipLimit).Get("/.well-known/jwks.json", authHandler.HandleJWKS)
 
 	r.Route("/api/v1", func(r chi.Router) {
 
 		// auth routes
 		r.Route("/auth", func(r chi.Router) {
-			r.Get("/google", authHandler.HandleGoogleLogin)
-			r.Get("/google/callback", authHandler.HandleGoogleAuthCallback)
+			r.Use(authLimit)
+			r.Get("/{provider}", authHandler.HandleOAuthLogin)
+			r.Get("/{provider}/callback", authHandler.HandleOAuthCallback)
 			r.Post("/refresh", authHandler.HandleRefresh)
+			r.Post("/logout", authHandler.HandleLogout)
+			r.With(authMiddleware.UserAuthentication, userLimit).Post("/logout-all", authHandler.HandleLogoutAll)
 		})
 
 		// user routes
 		r.Route("/users", func(r chi.Router) {
-			r.Get("/u/{username}", userHandler.HandleGetUserByUsername)
-			r.Get("/u/{username}/posts", userHandler.HandleGetUserPosts)
-			r.Get("/u/{username}/liked-posts", userHandler.HandleGetLikedPosts)
-			r.Get("/{userID}", userHandler.HandleGetUserByID)
+			r.Group(func(r chi.Router) {
+				r.Use(ipLimit)
+				r.Get("/u/{username}", userHandler.HandleGetUserByUsername)
+				r.Get("/u/{username}/posts", userHandler.HandleGetUserPosts)
+				r.Get("/u/{username}/liked-posts", userHandler.HandleGetLikedPosts)
+				r.Get("/u/{username}/followers", userHandler.HandleGetFollowers)
+				r.Get("/u/{username}/following", userHandler.HandleGetFollowing)
+				r.Get("/{userID}", userHandler.HandleGetUserByID)
+			})
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.UserAuthentication) // Apply authentication middleware to all /users routes
+				r.Use(userLimit)
 				r.Get("/me", userHandler.HandleGetCurrentUser)
+				r.Get("/me/drafts", userHandler.HandleGetDrafts)
+				r.With(usernameLimit).Patch("/me/username", userHandler.HandleUpdateUsername)
+				r.Get("/me/sessions", userHandler.HandleGetSessions)
+				r.Delete("/me/sessions/{sessionID}", userHandler.HandleRevokeSession)
 				r.Patch("/{userID}", userHandler.HandleUpdateUser)
 				r.Delete("/{userID}", userHandler.HandleDeleteCurrentUser)
+				r.Post("/{userID}/follow", userHandler.HandleFollowUser)
+				r.Delete("/{userID}/follow", userHandler.HandleUnfollowUser)
+				r.Post("/{userID}/block", userHandler.HandleBlockUser)
+				r.Delete("/{userID}/block", userHandler.HandleUnblockUser)
+				r.Post("/{userID}/mute", userHandler.HandleMuteUser)
+				r.Delete("/{userID}/mute", userHandler.HandleUnmuteUser)
 			})
 		})
 
 		// post routes
 		r.Route("/posts", func(r chi.Router) {
-			r.Get("/", postHandler.HandleGetPosts)
-			r.Get("/id/{postID}", postHandler.HandleGetPostByID)
-			r.Get("/{slug}", postHandler.HandleGetPostsBySlug)
-			r.Get("/{slug}/comments", postHandler.HandleGetCommentsByPostSlug)
+			r.Group(func(r chi.Router) {
+				r.Use(ipLimit)
+				r.Get("/", postHandler.HandleGetPosts)
+				r.Get("/id/{postID}", postHandler.HandleGetPostByID)
+				r.Get("/{slug}", postHandler.HandleGetPostsBySlug)
+				r.Get("/{slug}/comments", postHandler.HandleGetCommentsByPostSlug)
+			})
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.UserAuthentication)
-				r.Post("/", postHandler.HandleCreatePost)
+				r.Use(userLimit)
+				r.Get("/feed", postHandler.HandleGetFeed)
+				r.With(postLimit).Post("/", postHandler.HandleCreatePost)
 				r.Put("/{postID}", postHandler.HandleUpdatePost)
 				r.Delete("/{postID}", postHandler.HandleDeletePost)
-				r.Post("/{postID}/likes", postHandler.HandlePostLikes)
+				r.Post("/{postID}/restore", postHandler.HandleRestorePost)
+				r.With(likeLimit).Post("/{postID}/likes", postHandler.HandlePostLikes)
+				r.Post("/{postID}/publish", postHandler.HandlePublishPost)
+				r.Post("/{postID}/unpublish", postHandler.HandleUnpublishPost)
+				r.Get("/{postID}/revisions", postHandler.HandleGetPostRevisions)
+				r.Get("/{postID}/revisions/{n}", postHandler.HandleGetPostRevision)
+				r.Post("/{postID}/revisions/{n}/restore", postHandler.HandleRestorePostRevision)
+				r.With(authMiddleware.RequireRole(utils.RoleModerator)).Post("/{postID}/hide", postHandler.HandleHidePost)
+				r.With(authMiddleware.RequireRole(utils.RoleModerator)).Post("/{postID}/unhide", postHandler.HandleUnhidePost)
 			})
 		})
 
 		// comment routes
 		r.Route("/comments", func(r chi.Router) {
 			r.Use(authMiddleware.UserAuthentication)
-			r.Post("/", commentHandler.HandleCreateComment)
+			r.Use(userLimit)
+			r.With(commentLimit).Post("/", commentHandler.HandleCreateComment)
 			r.Delete("/{commentID}", commentHandler.HandleDeleteComment)
-			r.Patch("/{commentID}", commentHandler.HandleUpdateComment)
+			r.With(commentLimit).Patch("/{commentID}", commentHandler.HandleUpdateComment)
+			r.With(likeLimit).Post("/{commentID}/likes", commentHandler.HandleCommentLikes)
+			r.With(authMiddleware.RequireRole(utils.RoleModerator)).Post("/{commentID}/hide", commentHandler.HandleHideComment)
+			r.With(authMiddleware.RequireRole(utils.RoleModerator)).Post("/{commentID}/unhide", commentHandler.HandleUnhideComment)
+		})
+
+		// report routes
+		r.Route("/reports", func(r chi.Router) {
+			r.Use(authMiddleware.UserAuthentication)
+			r.Use(userLimit)
+			r.With(reportLimit).Post("/", reportHandler.HandleCreateReport)
+		})
+
+		// moderation routes
+		r.Route("/moderation", func(r chi.Router) {
+			r.Use(authMiddleware.UserAuthentication)
+			r.Use(authMiddleware.RequireRole(utils.RoleModerator))
+			r.Use(userLimit)
+			r.Get("/actions", moderationHandler.HandleGetModerationActions)
+			r.Get("/reports", reportHandler.HandleGetReports)
+			r.Get("/reports/{reportID}", reportHandler.HandleGetReport)
+			r.Post("/reports/{reportID}/resolve", reportHandler.HandleResolveReport)
+			r.Get("/filter-verdicts", moderationHandler.HandleGetFilterVerdicts)
+			r.Get("/filter-verdicts/{verdictID}", moderationHandler.HandleGetFilterVerdict)
+			r.Post("/filter-verdicts/{verdictID}/review", moderationHandler.HandleReviewFilterVerdict)
+			r.Post("/users/{userID}/suspend", moderationHandler.HandleSuspendUser)
+			r.Post("/users/{userID}/unsuspend", moderationHandler.HandleUnsuspendUser)
+			r.With(authMiddleware.RequireRole(utils.RoleAdmin)).Patch("/users/{userID}/role", moderationHandler.HandleUpdateUserRole)
 		})
 
 		// category routes
 		r.Route("/categories", func(r chi.Router) {
+			r.Use(ipLimit)
 			r.Get("/", categoryHandler.HandleGetCategories)
 		})
 	})
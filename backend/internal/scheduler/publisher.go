@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"go.uber.org/zap"
+)
+
+// publishBatchSize caps how many posts a single replica publishes per query
+const publishBatchSize = 100
+
+// PostPublisher periodically publishes posts whose publish_at time has passed.
+// Due rows are claimed with FOR UPDATE SKIP LOCKED, so several API replicas can
+// run it at the same time without publishing a post twice.
+type PostPublisher struct {
+	repo     *sqlc.Queries
+	logger   *zap.SugaredLogger
+	interval time.Duration
+}
+
+func NewPostPublisher(repo *sqlc.Queries, logger *zap.SugaredLogger, interval time.Duration) *PostPublisher {
+	return &PostPublisher{
+		repo:     repo,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Start publishes due posts every interval until the context is cancelled
+func (p *PostPublisher) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Infof("post publisher started with interval %s", p.interval)
+
+	for {
+		p.publishDuePosts(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("post publisher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *PostPublisher) publishDuePosts(ctx context.Context) {
+	for {
+		posts, err := p.repo.PublishDuePosts(ctx, publishBatchSize)
+		if err != nil {
+			p.logger.Errorf("failed to publish scheduled posts: %s", err.Error())
+			return
+		}
+
+		for _, post := range posts {
+			p.logger.Infof("published scheduled post %s", post.ID.String())
+		}
+
+		// a partial batch means nothing else is due right now
+		if len(posts) < publishBatchSize {
+			return
+		}
+	}
+}
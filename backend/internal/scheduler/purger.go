@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
+	"go.uber.org/zap"
+)
+
+// DeletedPurger periodically hard-deletes accounts, posts and comments that were deleted
+// longer ago than the restore window. Purging is idempotent, so it is safe to run on
+// several API replicas at once.
+type DeletedPurger struct {
+	repo          *sqlc.Queries
+	logger        *zap.SugaredLogger
+	interval      time.Duration
+	restoreWindow time.Duration
+}
+
+func NewDeletedPurger(repo *sqlc.Queries, logger *zap.SugaredLogger, interval time.Duration, restoreWindow time.Duration) *DeletedPurger {
+	return &DeletedPurger{
+		repo:          repo,
+		logger:        logger,
+		interval:      interval,
+		restoreWindow: restoreWindow,
+	}
+}
+
+// Start purges expired deletions every interval until the context is cancelled
+func (p *DeletedPurger) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Infof("deleted content purger started with interval %s and restore window %s", p.interval, p.restoreWindow)
+
+	for {
+		p.purgeExpired(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("deleted content purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *DeletedPurger) purgeExpired(ctx context.Context) {
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-p.restoreWindow), Valid: true}
+
+	// accounts first, since purging one also removes everything it owns. Comment placeholders
+	// survive it without an author, so the replies below them stay in place.
+	purges := []struct {
+		name  string
+		purge func(context.Context, pgtype.Timestamptz) (int64, error)
+	}{
+		{"users", p.repo.PurgeDeletedUsers},
+		{"posts", p.repo.PurgeDeletedPosts},
+		{"comments", p.repo.PurgeDeletedComments},
+	}
+	for _, purge := range purges {
+		count, err := purge.purge(ctx, cutoff)
+		if err != nil {
+			p.logger.Errorf("failed to purge deleted %s: %s", purge.name, err.Error())
+			continue
+		}
+		if count > 0 {
+			p.logger.Infof("purged %d deleted %s", count, purge.name)
+		}
+	}
+}
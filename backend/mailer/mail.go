@@ -79,8 +79,9 @@ func (m *Mailer) SendAccountDeletionEmail(toEmail string, username string) error
 	defer cancel()
 
 	subject := "Your BlogSphere Account Has Been Deleted"
-	text := fmt.Sprintf(accountDeletionEmailTextTemplate, username)
-	html := fmt.Sprintf(accountDeletionEmailHTMLTemplate, username)
+	restoreDays := config.Envs.RESTORE_WINDOW_DAYS
+	text := fmt.Sprintf(accountDeletionEmailTextTemplate, username, restoreDays, restoreDays)
+	html := fmt.Sprintf(accountDeletionEmailHTMLTemplate, username, restoreDays, restoreDays)
 
 	from := mailersend.From{
 		Name:  "BlogSphere",
@@ -89,9 +89,9 @@ All your data, including:
 - Comments and interactions
 - Account preferences
 
-has been permanently removed from our servers. We're sorry to see you go, but we respect your decision.
+has been removed from BlogSphere and will be permanently deleted from our servers in %d days. We're sorry to see you go, but we respect your decision.
 
-If this deletion was made in error or you have any concerns, please contact our support team immediately. Note that account recovery may not be possible after deletion.
+If this deletion was made in error, simply sign in again within %d days to reactivate your account along with your posts and comments. After that, account recovery is not possible.
 
 We'd love to hear your feedback about your experience on BlogSphere. Your insights help us improve our platform for the developer community.
 
@@ -134,7 +134,7 @@ const accountDeletionEmailHTMLTemplate = `<!DOCTYPE html>
 				This email confirms that your BlogSphere account has been <strong>successfully deleted</strong> from our platform.
 			</p>
 			<div class="info-box">
-				<h3>🗑️ The following data has been permanently removed:</h3>
+				<h3>🗑️ The following data has been removed and will be permanently deleted in %d days:</h3>
 				<ul>
 					<li>Your profile information</li>
 					<li>Published blog posts</li>
@@ -146,7 +146,7 @@ const accountDeletionEmailHTMLTemplate = `<!DOCTYPE html>
 				We're sorry to see you go, but we respect your decision. Thank you for being part of the BlogSphere community.
 			</p>
 			<div class="warning">
-				⚠️ <strong>Important:</strong> If this deletion was made in error or you have any concerns, please contact our support team immediately. Note that account recovery may not be possible after deletion.
+				⚠️ <strong>Important:</strong> If this deletion was made in error, simply sign in again within %d days to reactivate your account along with your posts and comments. After that, account recovery is not possible.
 			</div>
 			<p class="message">
 				We'd love to hear your feedback about your experience on BlogSphere. Your insights help us improve our platform for the developer community.
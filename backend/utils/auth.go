@@ -2,29 +2,64 @@ package utils
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/neevan0842/BlogSphere/backend/config"
+	"github.com/neevan0842/BlogSphere/backend/database/sqlc"
 )
 
 type contextKey string
 
-const UserContextKey contextKey = "userID"
+const (
+	UserContextKey     contextKey = "userID"
+	SessionContextKey  contextKey = "sessionID"
+	AuthUserContextKey contextKey = "user"
+)
+
+// Token types, stored in the "type" claim so a refresh token can't be used as an access token
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// TokenClaims are the claims BlogSphere puts in its JWTs
+type TokenClaims struct {
+	UserID    string
+	SessionID string
+	TokenID   string // only set on refresh tokens
+	Type      string
+}
 
-func CreateJWT(userID string, expirationInMinutes int64) (string, error) {
+func CreateJWT(claims TokenClaims, expirationInMinutes int64) (string, error) {
 	secret := []byte(config.Envs.JWT_SECRET)
 	expiration := time.Minute * time.Duration(expirationInMinutes)
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		jwt.MapClaims{
-			"userID": userID,
-			"exp":    time.Now().Add(expiration).Unix(),
-			"iat":    time.Now().Unix(),
-		})
+	mapClaims := jwt.MapClaims{
+		"userID": claims.UserID,
+		"sid":    claims.SessionID,
+		"type":   claims.Type,
+		"exp":    time.Now().Add(expiration).Unix(),
+		"iat":    time.Now().Unix(),
+	}
+	if claims.TokenID != "" {
+		mapClaims["jti"] = claims.TokenID
+	}
+
+	// sign with the active asymmetric key when one is configured, naming it in the kid header
+	if jwtKeys != nil {
+		token := jwt.NewWithClaims(jwtKeys.signingKey.method, mapClaims)
+		token.Header["kid"] = jwtKeys.signingKey.kid
+		return token.SignedString(jwtKeys.signingKey.private)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
 	tokenString, err := token.SignedString(secret)
 	if err != nil {
 		return "", err
@@ -51,45 +86,97 @@ func SetCookie(w http.ResponseWriter, name string, value string, expirationInMin
 	http.SetCookie(w, &cookie)
 }
 
-func GetAccessAndRefreshTokens(userID string) (string, string, error) {
-	accessToken, err := CreateJWT(userID, config.Envs.ACCESS_TOKEN_EXPIRE_MINUTES)
+// GetAccessAndRefreshTokens issues a token pair for a session. refreshTokenID is the
+// ID whose hash is stored server-side for the refresh token.
+func GetAccessAndRefreshTokens(userID string, sessionID string, refreshTokenID string) (string, string, error) {
+	accessToken, err := CreateJWT(TokenClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		Type:      TokenTypeAccess,
+	}, config.Envs.ACCESS_TOKEN_EXPIRE_MINUTES)
 	if err != nil {
 		return "", "", err
 	}
-	refreshToken, err := CreateJWT(userID, config.Envs.REFRESH_TOKEN_EXPIRE_MINUTES)
+	refreshToken, err := CreateJWT(TokenClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenID:   refreshTokenID,
+		Type:      TokenTypeRefresh,
+	}, config.Envs.REFRESH_TOKEN_EXPIRE_MINUTES)
 	if err != nil {
 		return "", "", err
 	}
 	return accessToken, refreshToken, nil
 }
 
+// HashTokenID returns the hex SHA-256 of a token ID, which is what gets stored in the database
+func HashTokenID(tokenID string) string {
+	sum := sha256.Sum256([]byte(tokenID))
+	return hex.EncodeToString(sum[:])
+}
+
 func PermissionDenied(w http.ResponseWriter) {
 	WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
 }
 
 func GetUserIDFromToken(w http.ResponseWriter, tokenString string) (pgtype.UUID, error) {
+	claims, err := ParseToken(tokenString, TokenTypeAccess)
+	if err != nil {
+		return pgtype.UUID{}, err
+	}
+	userIDUUID, err := StrToUUID(claims.UserID)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("invalid userID in token: %s", err.Error())
+	}
+	return userIDUUID, nil
+}
+
+// ParseToken validates tokenString and checks that it is of the expected type
+func ParseToken(tokenString string, tokenType string) (TokenClaims, error) {
 	if tokenString == "" {
-		return pgtype.UUID{}, fmt.Errorf("missing token")
+		return TokenClaims{}, fmt.Errorf("missing token")
 	}
 	token, err := validateJWT(tokenString)
-	if err != nil || !token.Valid {
-		return pgtype.UUID{}, fmt.Errorf("invalid token: %s", err.Error())
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("invalid token: %s", err.Error())
+	}
+	if !token.Valid {
+		return TokenClaims{}, fmt.Errorf("invalid token")
 	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	userID, ok := claims["userID"].(string)
-	if !ok {
-		return pgtype.UUID{}, fmt.Errorf("invalid token claims: userID not found")
+	mapClaims := token.Claims.(jwt.MapClaims)
+	if t, _ := mapClaims["type"].(string); t != tokenType {
+		return TokenClaims{}, fmt.Errorf("invalid token type: expected %s token", tokenType)
 	}
-	userIDUUID, err := StrToUUID(userID)
-	if err != nil {
-		return pgtype.UUID{}, fmt.Errorf("invalid userID in token: %s", err.Error())
+	userID, ok := mapClaims["userID"].(string)
+	if !ok {
+		return TokenClaims{}, fmt.Errorf("invalid token claims: userID not found")
 	}
-	return userIDUUID, nil
+	sessionID, _ := mapClaims["sid"].(string)
+	tokenID, _ := mapClaims["jti"].(string)
+	return TokenClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		TokenID:   tokenID,
+		Type:      tokenType,
+	}, nil
 }
 
 func validateJWT(tokenString string) (*jwt.Token, error) {
 	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// with asymmetric keys configured, HS256 tokens are no longer accepted
+		if jwtKeys != nil {
+			kid, _ := token.Header["kid"].(string)
+			key, ok := jwtKeys.keys[kid]
+			if !ok {
+				return nil, fmt.Errorf("unknown signing key: %q", kid)
+			}
+			if token.Method.Alg() != key.method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return key.public, nil
+		}
+
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
@@ -114,3 +201,23 @@ func GetUserIDFromContext(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(UserContextKey).(string)
 	return userID, ok
 }
+
+// GetUserFromContext returns the authenticated user, including their role, as loaded by the auth middleware
+func GetUserFromContext(ctx context.Context) (sqlc.User, bool) {
+	user, ok := ctx.Value(AuthUserContextKey).(sqlc.User)
+	return user, ok
+}
+
+func GetSessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(SessionContextKey).(string)
+	return sessionID, ok
+}
+
+// GetClientIP returns the client IP, as set on RemoteAddr by the RealIP middleware
+func GetClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []DiffLine
+	}{
+		{
+			name: "both empty",
+			want: []DiffLine{},
+		},
+		{
+			name:    "unchanged",
+			oldText: "a\nb",
+			newText: "a\nb",
+			want:    []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+		{
+			name:    "from empty",
+			newText: "a\nb",
+			want:    []DiffLine{{DiffInsert, "a"}, {DiffInsert, "b"}},
+		},
+		{
+			name:    "to empty",
+			oldText: "a\nb",
+			want:    []DiffLine{{DiffDelete, "a"}, {DiffDelete, "b"}},
+		},
+		{
+			name:    "line changed in the middle",
+			oldText: "a\nb\nc",
+			newText: "a\nx\nc",
+			want:    []DiffLine{{DiffEqual, "a"}, {DiffDelete, "b"}, {DiffInsert, "x"}, {DiffEqual, "c"}},
+		},
+		{
+			name:    "line inserted and deleted",
+			oldText: "a\nb\nc\nd",
+			newText: "b\nc\ne\nd",
+			want:    []DiffLine{{DiffDelete, "a"}, {DiffEqual, "b"}, {DiffEqual, "c"}, {DiffInsert, "e"}, {DiffEqual, "d"}},
+		},
+		{
+			name:    "windows line endings",
+			oldText: "a\r\nb",
+			newText: "a\nb",
+			want:    []DiffLine{{DiffEqual, "a"}, {DiffEqual, "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.oldText, tt.newText); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines(%q, %q) = %v, want %v", tt.oldText, tt.newText, got, tt.want)
+			}
+		})
+	}
+}
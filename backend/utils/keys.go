@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// verificationKey is a key tokens can be verified with. Keys that still have their
+// private half on disk can also sign; retired keys only ship their public half.
+type verificationKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	public  crypto.PublicKey
+	private crypto.Signer
+}
+
+type keySet struct {
+	keys       map[string]*verificationKey
+	signingKey *verificationKey
+}
+
+// jwtKeys is nil when no key directory is configured, in which case tokens are signed with JWT_SECRET (HS256)
+var jwtKeys *keySet
+
+// LoadSigningKeys loads the JWT keys in dir and selects signingKID as the active signing key.
+// Each file is named after its key ID: <kid>.pem holds a private key (RSA or Ed25519) and
+// <kid>.pub.pem holds the public key of a retired key that is only used for verification.
+// An empty dir keeps HS256 signing with JWT_SECRET.
+func LoadSigningKeys(dir string, signingKID string) error {
+	if dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return fmt.Errorf("failed to list JWT keys: %s", err.Error())
+	}
+
+	set := &keySet{keys: make(map[string]*verificationKey)}
+	for _, file := range files {
+		kid := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file), ".pem"), ".pub")
+		key, err := loadKeyFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to load JWT key %s: %s", file, err.Error())
+		}
+		key.kid = kid
+
+		// a private key supersedes a public file with the same ID
+		if existing, ok := set.keys[kid]; ok && existing.private != nil {
+			continue
+		}
+		set.keys[kid] = key
+	}
+
+	signingKey, ok := set.keys[signingKID]
+	if !ok || signingKey.private == nil {
+		return fmt.Errorf("no private JWT key found for signing key ID %q in %s", signingKID, dir)
+	}
+	set.signingKey = signingKey
+
+	jwtKeys = set
+	return nil
+}
+
+func loadKeyFile(file string) (*verificationKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var parsed any
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA keys must be at least 2048 bits")
+		}
+		return &verificationKey{method: jwt.SigningMethodRS256, public: &k.PublicKey, private: k}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA keys must be at least 2048 bits")
+		}
+		return &verificationKey{method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return &verificationKey{method: jwt.SigningMethodEdDSA, public: k.Public(), private: k}, nil
+	case ed25519.PublicKey:
+		return &verificationKey{method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// GetJWKS returns every key tokens may currently be verified with.
+// It is empty when tokens are signed with the HS256 secret.
+func GetJWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	if jwtKeys == nil {
+		return jwks
+	}
+
+	kids := make([]string, 0, len(jwtKeys.keys))
+	for kid := range jwtKeys.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	for _, kid := range kids {
+		key := jwtKeys.keys[kid]
+		jwk := JWK{Kid: key.kid, Use: "sig", Alg: key.method.Alg()}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
@@ -0,0 +1,26 @@
+package utils
+
+// User roles, from least to most privileged
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var roleRank = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+// HasRole reports whether role grants at least the permissions of required. Admins can do
+// everything moderators can, and unknown roles are granted nothing.
+func HasRole(role, required string) bool {
+	rank, ok := roleRank[role]
+	return ok && rank >= roleRank[required]
+}
+
+// CanModerate reports whether role may hide or delete other users' content
+func CanModerate(role string) bool {
+	return HasRole(role, RoleModerator)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -11,7 +13,72 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-var Validate = validator.New()
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// slug: lowercase letters, digits and single hyphens, e.g. "my-first-post", and not reserved
+	v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return IsValidSlug(fl.Field().String())
+	})
+	v.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return IsValidUsername(fl.Field().String())
+	})
+	return v
+}
+
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 30
+)
+
+// usernamePattern allows lowercase letters, digits, underscores and hyphens
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// reservedUsernames would clash with routes or pass for official accounts
+var reservedUsernames = map[string]bool{
+	"me": true, "u": true, "admin": true, "administrator": true, "api": true, "auth": true,
+	"blogsphere": true, "feed": true, "help": true, "login": true, "logout": true, "moderator": true,
+	"null": true, "posts": true, "root": true, "settings": true, "signin": true, "signup": true,
+	"staff": true, "support": true, "system": true, "undefined": true, "users": true,
+}
+
+// IsValidUsername reports whether username has an allowed length and charset and is not reserved
+func IsValidUsername(username string) bool {
+	return len(username) >= UsernameMinLength &&
+		len(username) <= UsernameMaxLength &&
+		usernamePattern.MatchString(username) &&
+		!reservedUsernames[username]
+}
+
+// reservedSlugs would clash with the routes under /posts or the frontend's post pages
+var reservedSlugs = map[string]bool{
+	"create": true, "drafts": true, "edit": true, "feed": true, "id": true, "new": true,
+	"search": true,
+}
+
+// IsValidSlug reports whether s is a well-formed post slug that is not reserved
+func IsValidSlug(s string) bool {
+	return slug.IsSlug(s) && !reservedSlugs[s]
+}
+
+// NormalizeUsername turns s (e.g. an email local-part) into a username candidate by lowercasing
+// it, replacing disallowed characters with hyphens and trimming it to the maximum length
+func NormalizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	username := strings.Trim(b.String(), "-")
+	if len(username) > UsernameMaxLength {
+		username = strings.TrimRight(username[:UsernameMaxLength], "-")
+	}
+	return username
+}
 
 func ParseJSON(r *http.Request, payload any) error {
 	if r.Body == nil {
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsValidUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		want     bool
+	}{
+		{"octocat", true},
+		{"new-user_42", true},
+		{"abc", true},
+		{strings.Repeat("a", UsernameMaxLength), true},
+		{"ab", false},
+		{strings.Repeat("a", UsernameMaxLength+1), false},
+		{"Octocat", false},
+		{"new user", false},
+		{"new.user", false},
+		{"", false},
+		{"admin", false},
+		{"me", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidUsername(tt.username); got != tt.want {
+			t.Errorf("IsValidUsername(%q) = %t, want %t", tt.username, got, tt.want)
+		}
+	}
+}
+
+func TestUsernameValidation(t *testing.T) {
+	type payload struct {
+		Username string `validate:"required,username"`
+	}
+	tests := []struct {
+		username string
+		wantErr  bool
+	}{
+		{"octocat", false},
+		{"no", true},
+		{"Octocat", true},
+		{"settings", true},
+	}
+	for _, tt := range tests {
+		err := Validate.Struct(payload{Username: tt.username})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, want error %t", tt.username, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"octocat", "octocat"},
+		{"New.User", "new-user"},
+		{"--jane doe--", "jane-doe"},
+		{"émile", "mile"},
+		{strings.Repeat("a", 29) + "-b", strings.Repeat("a", 29)},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeUsername(tt.input); got != tt.want {
+			t.Errorf("NormalizeUsername(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
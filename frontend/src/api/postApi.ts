@@ -59,7 +59,7 @@ export const getCommentsByPostSlug = async (
       return null;
     }
     const response = await api.get(`/posts/${slug.trim()}/comments`);
-    return response.data as CommentWithAuthor[];
+    return response.data.comments as CommentWithAuthor[];
   } catch (error) {
     return null;
   }
@@ -68,6 +68,8 @@ export const refreshAccessToken = async (): Promise<boolean> => {
     const { setUser } = useUserStore.getState();
     setUser(user);
     localStorage.setItem("access-token", response.data.access_token);
+    // refresh tokens are single-use, keep the rotated one
+    localStorage.setItem("refresh-token", response.data.refresh_token);
     return true;
   } catch (error) {
     return false;
@@ -99,7 +101,7 @@ export const getUserDetailsFromUsername = async (
 export const getUserPosts = async (username: string): Promise<PostType[]> => {
   try {
     const response = await api.get(`/users/u/${username}/posts`);
-    return response.data as PostType[];
+    return response.data.posts as PostType[];
   } catch (error) {
     return [];
   }
@@ -110,13 +112,18 @@ export const getUserLikedPosts = async (
 ): Promise<PostType[]> => {
   try {
     const response = await api.get(`/users/u/${username}/liked-posts`);
-    return response.data as PostType[];
+    return response.data.posts as PostType[];
   } catch (error) {
     return [];
   }
 };
 
 export const logout = () => {
+  // revoke the session server-side; local tokens are cleared regardless
+  const refreshToken = localStorage.getItem("refresh-token");
+  if (refreshToken) {
+    api.post("/auth/logout", { refresh_token: refreshToken }).catch(() => {});
+  }
   localStorage.removeItem("access-token");
   localStorage.removeItem("refresh-token");
   const { clearUser } = useUserStore.getState();
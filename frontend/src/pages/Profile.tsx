@@ -161,7 +161,9 @@ const Profile = () => {
                     <h1 className="text-3xl md:text-4xl font-bold text-foreground mb-2">
                       {user.username || "Anonymous"}
                     </h1>
-                    <p className="text-muted-foreground mb-4">{user.email}</p>
+                    {user.email && (
+                      <p className="text-muted-foreground mb-4">{user.email}</p>
+                    )}
                     <p className="text-foreground mb-6 max-w-xl">
                       {user.description || "No description available."}
                     </p>
@@ -348,7 +350,7 @@ const Profile = () => {
         onClose={() => setShowDeleteAccountModal(false)}
         onConfirm={handleDeleteAccountConfirm}
         title="Delete Account"
-        message="Are you sure you want to delete your account? This action cannot be undone and all your posts will be permanently deleted."
+        message="Are you sure you want to delete your account? Your profile, posts and comments will be removed, and permanently deleted unless you sign in again to reactivate your account before the restore window ends."
         confirmText="Delete Account"
         cancelText="Cancel"
         variant="danger"
@@ -5,13 +5,21 @@
 // Users table
 export interface User {
   id: string; // UUID
-  google_id: string;
   username: string | null;
-  email: string;
+  email?: string; // only present on your own account or when the user shows it
   description: string | null;
   avatar_url: string | null;
   created_at: string; // TIMESTAMPTZ
   updated_at: string; // TIMESTAMPTZ
+  display_name: string | null;
+  website_url: string | null;
+  github_url: string | null;
+  twitter_url: string | null;
+  linkedin_url: string | null;
+  location: string | null;
+  custom_avatar_url: string | null;
+  show_email?: boolean;
+  role?: "user" | "moderator" | "admin"; // only present on your own account
 }
 
 // Categories table
@@ -30,6 +38,7 @@ export interface Post {
   slug: string;
   body: string;
   is_published: boolean;
+  is_hidden: boolean;
   created_at: string; // TIMESTAMPTZ
   updated_at: string; // TIMESTAMPTZ
 }
@@ -40,6 +49,7 @@ export interface Comment {
   post_id: string; // UUID reference to posts
   user_id: string; // UUID reference to users
   body: string;
+  is_hidden: boolean;
   created_at: string; // TIMESTAMPTZ
   updated_at: string; // TIMESTAMPTZ
 }
@@ -104,10 +114,14 @@ export interface CategoryDisplay extends Category {
 // Author info returned in post responses (simplified User)
 export interface PostAuthorType {
   id: string;
-  google_id: string;
   username: string;
-  email: string;
+  display_name: string;
   avatar_url: string;
+  website_url: string;
+  github_url: string;
+  twitter_url: string;
+  linkedin_url: string;
+  location: string;
   created_at: string;
   updated_at: string;
 }
@@ -128,6 +142,7 @@ export interface PostType {
   slug: string;
   body: string;
   is_published: boolean;
+  is_hidden: boolean;
   created_at: string;
   updated_at: string;
   author: PostAuthorType;